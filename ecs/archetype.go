@@ -0,0 +1,67 @@
+package ecs
+
+// column stores every value of one component type within an archetype
+type column interface {
+	// copyTo appends the value at row onto dst, which must hold the same type
+	copyTo(row int, dst column)
+	// swapRemove removes the value at row by moving the last value into it
+	swapRemove(row int)
+}
+
+// typedColumn is a densely packed column of component values
+type typedColumn[T any] struct {
+	data []T
+}
+
+func (c *typedColumn[T]) copyTo(row int, dst column) {
+	target := dst.(*typedColumn[T])
+	target.data = append(target.data, c.data[row])
+}
+
+func (c *typedColumn[T]) swapRemove(row int) {
+	last := len(c.data) - 1
+	c.data[row] = c.data[last]
+
+	var zero T
+	c.data[last] = zero
+	c.data = c.data[:last]
+}
+
+// archetype holds all entities sharing exactly the same component types
+type archetype struct {
+	types    []componentID // Sorted component types
+	columns  map[componentID]column
+	entities []Entity // Entity owning each row
+}
+
+// add appends an entity row and returns its index. Component values are
+// appended to the columns separately.
+func (a *archetype) add(entity Entity) int {
+	a.entities = append(a.entities, entity)
+	return len(a.entities) - 1
+}
+
+// swapRemove removes a row from every column. It returns the entity that was
+// moved into the row, if any.
+func (a *archetype) swapRemove(row int) (Entity, bool) {
+	for _, col := range a.columns {
+		col.swapRemove(row)
+	}
+
+	last := len(a.entities) - 1
+	moved := a.entities[last]
+	a.entities[row] = moved
+	a.entities = a.entities[:last]
+
+	return moved, row != last
+}
+
+// has reports whether the archetype stores every given component type
+func (a *archetype) has(ids ...componentID) bool {
+	for _, id := range ids {
+		if _, ok := a.columns[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package ecs
+
+// Each calls fn for every entity that has component A, in archetype creation
+// order then row order. Structural changes made inside fn are deferred until
+// the outermost query returns.
+func Each[A any](w *World, fn func(Entity, *A)) {
+	a := componentIDOf[A](w)
+
+	w.beginQuery()
+	defer w.endQuery()
+
+	for _, arch := range w.ordered {
+		if !arch.has(a) {
+			continue
+		}
+
+		colA := arch.columns[a].(*typedColumn[A]).data
+		for row, entity := range arch.entities {
+			fn(entity, &colA[row])
+		}
+	}
+}
+
+// Each2 calls fn for every entity that has components A and B
+func Each2[A, B any](w *World, fn func(Entity, *A, *B)) {
+	a, b := componentIDOf[A](w), componentIDOf[B](w)
+
+	w.beginQuery()
+	defer w.endQuery()
+
+	for _, arch := range w.ordered {
+		if !arch.has(a, b) {
+			continue
+		}
+
+		colA := arch.columns[a].(*typedColumn[A]).data
+		colB := arch.columns[b].(*typedColumn[B]).data
+		for row, entity := range arch.entities {
+			fn(entity, &colA[row], &colB[row])
+		}
+	}
+}
+
+// Count returns the number of entities that have component A
+func Count[A any](w *World) int {
+	a := componentIDOf[A](w)
+
+	count := 0
+	for _, arch := range w.ordered {
+		if arch.has(a) {
+			count += len(arch.entities)
+		}
+	}
+	return count
+}
@@ -0,0 +1,260 @@
+// Package ecs implements a small archetype-based entity-component-system core.
+//
+// Entities with the same set of component types share an archetype, which
+// stores each component type in its own densely packed column. Queries walk
+// the matching archetypes' columns directly.
+package ecs
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Entity identifies an entity in a World. IDs are never reused.
+type Entity uint32
+
+// componentID is the dense index assigned to a component type
+type componentID int
+
+// location records where an entity's components live
+type location struct {
+	archetype *archetype
+	row       int
+}
+
+// World owns all entities, their components and shared resources
+type World struct {
+	archetypes   map[string]*archetype
+	ordered      []*archetype // Creation order, for deterministic queries
+	locations    map[Entity]location
+	componentIDs map[reflect.Type]componentID
+	newColumns   []func() column // Column constructors indexed by componentID
+	resources    map[reflect.Type]any
+	nextEntity   Entity
+
+	iterating int      // Depth of running queries
+	pending   []func() // Structural changes deferred until queries finish
+}
+
+// NewWorld creates an empty world
+func NewWorld() *World {
+	return &World{
+		archetypes:   make(map[string]*archetype),
+		locations:    make(map[Entity]location),
+		componentIDs: make(map[reflect.Type]componentID),
+		resources:    make(map[reflect.Type]any),
+		nextEntity:   1,
+	}
+}
+
+// Spawn creates a new entity without components
+func (w *World) Spawn() Entity {
+	entity := w.nextEntity
+	w.nextEntity++
+
+	empty := w.archetypeFor(nil)
+	w.locations[entity] = location{archetype: empty, row: empty.add(entity)}
+	return entity
+}
+
+// Alive reports whether the entity exists and has not been despawned
+func (w *World) Alive(entity Entity) bool {
+	_, ok := w.locations[entity]
+	return ok
+}
+
+// Len returns the number of live entities
+func (w *World) Len() int {
+	return len(w.locations)
+}
+
+// Despawn removes an entity and all of its components. Inside a query the
+// removal is deferred until the outermost query returns.
+func (w *World) Despawn(entity Entity) {
+	if w.iterating > 0 {
+		w.pending = append(w.pending, func() { w.Despawn(entity) })
+		return
+	}
+
+	loc, ok := w.locations[entity]
+	if !ok {
+		return
+	}
+
+	w.removeRow(loc)
+	delete(w.locations, entity)
+}
+
+// Add attaches a component to an entity, replacing any existing value of the
+// same type. Inside a query the change is deferred until the outermost query
+// returns.
+func Add[T any](w *World, entity Entity, value T) {
+	if w.iterating > 0 {
+		w.pending = append(w.pending, func() { Add(w, entity, value) })
+		return
+	}
+
+	loc, ok := w.locations[entity]
+	if !ok {
+		return
+	}
+
+	id := componentIDOf[T](w)
+	if col, ok := loc.archetype.columns[id]; ok {
+		col.(*typedColumn[T]).data[loc.row] = value
+		return
+	}
+
+	types := append(slices.Clone(loc.archetype.types), id)
+	slices.Sort(types)
+	target := w.archetypeFor(types)
+
+	row := w.moveEntity(entity, loc, target)
+	target.columns[id].(*typedColumn[T]).data = append(target.columns[id].(*typedColumn[T]).data, value)
+	w.locations[entity] = location{archetype: target, row: row}
+}
+
+// Remove detaches a component from an entity. Inside a query the change is
+// deferred until the outermost query returns.
+func Remove[T any](w *World, entity Entity) {
+	if w.iterating > 0 {
+		w.pending = append(w.pending, func() { Remove[T](w, entity) })
+		return
+	}
+
+	loc, ok := w.locations[entity]
+	if !ok {
+		return
+	}
+
+	id := componentIDOf[T](w)
+	if _, ok := loc.archetype.columns[id]; !ok {
+		return
+	}
+
+	types := slices.DeleteFunc(slices.Clone(loc.archetype.types), func(t componentID) bool { return t == id })
+	target := w.archetypeFor(types)
+
+	row := w.moveEntity(entity, loc, target)
+	w.locations[entity] = location{archetype: target, row: row}
+}
+
+// Get returns a pointer to an entity's component. The pointer is only valid
+// until the next structural change (spawn, despawn, add or remove).
+func Get[T any](w *World, entity Entity) (*T, bool) {
+	loc, ok := w.locations[entity]
+	if !ok {
+		return nil, false
+	}
+
+	id, ok := w.componentIDs[reflect.TypeFor[T]()]
+	if !ok {
+		return nil, false
+	}
+
+	col, ok := loc.archetype.columns[id]
+	if !ok {
+		return nil, false
+	}
+	return &col.(*typedColumn[T]).data[loc.row], true
+}
+
+// Has reports whether an entity has a component of type T
+func Has[T any](w *World, entity Entity) bool {
+	_, ok := Get[T](w, entity)
+	return ok
+}
+
+// SetResource stores a world-wide singleton value of type T
+func SetResource[T any](w *World, value T) {
+	w.resources[reflect.TypeFor[T]()] = value
+}
+
+// Resource returns the world-wide singleton of type T
+func Resource[T any](w *World) (T, bool) {
+	value, ok := w.resources[reflect.TypeFor[T]()].(T)
+	return value, ok
+}
+
+// componentIDOf returns the ID for component type T, registering it if needed
+func componentIDOf[T any](w *World) componentID {
+	componentType := reflect.TypeFor[T]()
+	if id, ok := w.componentIDs[componentType]; ok {
+		return id
+	}
+
+	id := componentID(len(w.newColumns))
+	w.componentIDs[componentType] = id
+	w.newColumns = append(w.newColumns, func() column { return &typedColumn[T]{} })
+	return id
+}
+
+// archetypeFor returns the archetype for a sorted set of component types
+func (w *World) archetypeFor(types []componentID) *archetype {
+	var key strings.Builder
+	for _, id := range types {
+		key.WriteString(strconv.Itoa(int(id)))
+		key.WriteByte(',')
+	}
+
+	if arch, ok := w.archetypes[key.String()]; ok {
+		return arch
+	}
+
+	arch := &archetype{
+		types:   types,
+		columns: make(map[componentID]column, len(types)),
+	}
+	for _, id := range types {
+		arch.columns[id] = w.newColumns[id]()
+	}
+
+	w.archetypes[key.String()] = arch
+	w.ordered = append(w.ordered, arch)
+	return arch
+}
+
+// moveEntity copies an entity's shared components into the target archetype and
+// removes it from its current one. Columns the target has but the source lacks
+// are left for the caller to append.
+func (w *World) moveEntity(entity Entity, loc location, target *archetype) int {
+	row := target.add(entity)
+	for id, col := range loc.archetype.columns {
+		if dst, ok := target.columns[id]; ok {
+			col.copyTo(loc.row, dst)
+		}
+	}
+
+	w.removeRow(loc)
+	return row
+}
+
+// removeRow swap-removes a row and fixes up the location of the moved entity
+func (w *World) removeRow(loc location) {
+	if moved, ok := loc.archetype.swapRemove(loc.row); ok {
+		w.locations[moved] = location{archetype: loc.archetype, row: loc.row}
+	}
+}
+
+// beginQuery marks the start of an iteration
+func (w *World) beginQuery() {
+	w.iterating++
+}
+
+// endQuery applies deferred structural changes once the outermost query ends
+func (w *World) endQuery() {
+	w.iterating--
+	if w.iterating > 0 {
+		return
+	}
+
+	for len(w.pending) > 0 {
+		pending := w.pending
+		w.pending = nil
+		for _, change := range pending {
+			change()
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+)
+
+type position struct{ x, y int }
+
+type velocity struct{ dx, dy int }
+
+type tag struct{}
+
+func TestAddAndRemoveMoveBetweenArchetypes(t *testing.T) {
+	w := NewWorld()
+	entity := w.Spawn()
+
+	Add(w, entity, position{x: 1, y: 2})
+	Add(w, entity, velocity{dx: 3})
+	if pos, ok := Get[position](w, entity); !ok || *pos != (position{x: 1, y: 2}) {
+		t.Fatalf("position after adding velocity = %v, %v", pos, ok)
+	}
+	if vel, ok := Get[velocity](w, entity); !ok || *vel != (velocity{dx: 3}) {
+		t.Fatalf("velocity = %v, %v", vel, ok)
+	}
+
+	// Adding a component the entity already has replaces it in place
+	Add(w, entity, position{x: 5})
+	if pos, _ := Get[position](w, entity); *pos != (position{x: 5}) {
+		t.Errorf("replaced position = %v", *pos)
+	}
+
+	Remove[position](w, entity)
+	if Has[position](w, entity) {
+		t.Error("position is still attached after Remove")
+	}
+	if vel, ok := Get[velocity](w, entity); !ok || *vel != (velocity{dx: 3}) {
+		t.Errorf("velocity after removing position = %v, %v", vel, ok)
+	}
+	if Count[position](w) != 0 || Count[velocity](w) != 1 {
+		t.Errorf("counts = %d positions, %d velocities; want 0, 1", Count[position](w), Count[velocity](w))
+	}
+}
+
+func TestRemoveRowFixesMovedEntity(t *testing.T) {
+	w := NewWorld()
+	entities := make([]Entity, 4)
+	for i := range entities {
+		entities[i] = w.Spawn()
+		Add(w, entities[i], position{x: i})
+	}
+
+	// Despawning the first row moves the last entity into it; moving the
+	// second to another archetype does the same to the new last entity
+	w.Despawn(entities[0])
+	Add(w, entities[1], velocity{})
+
+	for i, entity := range entities[1:] {
+		pos, ok := Get[position](w, entity)
+		if !ok || pos.x != i+1 {
+			t.Errorf("entity %d position = %v, %v; want x = %d", entity, pos, ok, i+1)
+		}
+	}
+	if w.Len() != 3 {
+		t.Errorf("Len = %d, want 3", w.Len())
+	}
+}
+
+func TestGetAfterDespawn(t *testing.T) {
+	w := NewWorld()
+	entity := w.Spawn()
+	Add(w, entity, position{x: 1})
+	w.Despawn(entity)
+
+	if w.Alive(entity) {
+		t.Error("entity is alive after Despawn")
+	}
+	if pos, ok := Get[position](w, entity); ok || pos != nil {
+		t.Errorf("Get after despawn = %v, %v", pos, ok)
+	}
+
+	// Changes to a dead entity are ignored rather than resurrecting it
+	Add(w, entity, velocity{})
+	w.Despawn(entity)
+	if w.Alive(entity) || Count[velocity](w) != 0 {
+		t.Error("Add revived a despawned entity")
+	}
+
+	// IDs are never reused
+	if next := w.Spawn(); next == entity {
+		t.Errorf("Spawn reused despawned ID %d", entity)
+	}
+}
+
+func TestStructuralChangesInQueryAreDeferred(t *testing.T) {
+	w := NewWorld()
+	for i := range 4 {
+		Add(w, w.Spawn(), position{x: i})
+	}
+
+	var visited []int
+	Each(w, func(entity Entity, pos *position) {
+		visited = append(visited, pos.x)
+		Add(w, entity, velocity{dx: pos.x})
+		Add(w, w.Spawn(), position{x: 10 + pos.x})
+		if Has[velocity](w, entity) {
+			t.Errorf("entity %d moved archetype mid-query", entity)
+		}
+	})
+
+	// Each saw only the original entities, in row order
+	if want := []int{0, 1, 2, 3}; !slices.Equal(visited, want) {
+		t.Fatalf("visited %v, want %v", visited, want)
+	}
+	if Count[velocity](w) != 4 || Count[position](w) != 8 {
+		t.Errorf("after query: %d velocities, %d positions; want 4, 8", Count[velocity](w), Count[position](w))
+	}
+}
+
+func TestNestedQueriesApplyChangesAtOutermost(t *testing.T) {
+	w := NewWorld()
+	Add(w, w.Spawn(), position{})
+	Add(w, w.Spawn(), velocity{})
+
+	Each(w, func(Entity, *position) {
+		Each(w, func(entity Entity, _ *velocity) {
+			Add(w, entity, tag{})
+		})
+		if Count[tag](w) != 0 {
+			t.Error("inner query applied its changes before the outer query ended")
+		}
+	})
+
+	if Count[tag](w) != 1 {
+		t.Errorf("tags after query = %d, want 1", Count[tag](w))
+	}
+}
+
+func TestDespawnInsideEach(t *testing.T) {
+	w := NewWorld()
+	entities := make([]Entity, 6)
+	for i := range entities {
+		entities[i] = w.Spawn()
+		Add(w, entities[i], position{x: i})
+		if i%2 == 0 {
+			Add(w, entities[i], velocity{})
+		}
+	}
+
+	visited := 0
+	Each(w, func(entity Entity, pos *position) {
+		visited++
+		if pos.x%3 == 0 {
+			w.Despawn(entity)
+		}
+		// Despawning another entity mid-query must not skip or repeat rows
+		if pos.x == 1 {
+			w.Despawn(entities[5])
+		}
+	})
+
+	if visited != len(entities) {
+		t.Errorf("visited %d entities, want %d", visited, len(entities))
+	}
+
+	var remaining []int
+	Each(w, func(_ Entity, pos *position) { remaining = append(remaining, pos.x) })
+	slices.Sort(remaining)
+	if want := []int{1, 2, 4}; !slices.Equal(remaining, want) {
+		t.Errorf("remaining %v, want %v", remaining, want)
+	}
+	for _, entity := range entities {
+		if pos, ok := Get[position](w, entity); ok && w.Alive(entity) && entities[pos.x] != entity {
+			t.Errorf("entity %d has position of entity %d", entity, entities[pos.x])
+		}
+	}
+}
+
+func TestResource(t *testing.T) {
+	w := NewWorld()
+	if _, ok := Resource[int](w); ok {
+		t.Error("missing resource reported as present")
+	}
+
+	SetResource(w, 42)
+	if value, ok := Resource[int](w); !ok || value != 42 {
+		t.Errorf("Resource = %d, %v; want 42, true", value, ok)
+	}
+}
@@ -0,0 +1,94 @@
+// Package events provides a typed, synchronous event bus that lets game systems
+// and the navigator notify each other without holding direct references.
+package events
+
+import "reflect"
+
+// Bus dispatches typed events to their subscribers synchronously.
+//
+// Handlers for an event run in subscription order. Events published while a
+// handler is running are queued and delivered, in publish order, once the
+// current event has reached every handler, so dispatch order is deterministic.
+// A nil *Bus is valid and drops every event.
+type Bus struct {
+	subscribers map[reflect.Type][]*subscriber
+	queue       []queuedEvent
+	dispatching bool
+}
+
+// subscriber wraps a typed handler so handlers of all types share one map
+type subscriber struct {
+	handle func(any)
+	active bool
+}
+
+// queuedEvent is an event waiting to be delivered
+type queuedEvent struct {
+	eventType reflect.Type
+	event     any
+}
+
+// NewBus creates an empty event bus
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: make(map[reflect.Type][]*subscriber),
+	}
+}
+
+// Subscribe registers a handler for events of type T and returns a function
+// that removes it again
+func Subscribe[T any](bus *Bus, handler func(T)) (unsubscribe func()) {
+	if bus == nil {
+		return func() {}
+	}
+
+	eventType := reflect.TypeFor[T]()
+	sub := &subscriber{
+		handle: func(event any) { handler(event.(T)) },
+		active: true,
+	}
+	bus.subscribers[eventType] = append(bus.subscribers[eventType], sub)
+
+	return func() {
+		sub.active = false
+		subs := bus.subscribers[eventType]
+		for i, s := range subs {
+			if s == sub {
+				// Copy so an in-flight dispatch keeps iterating its own snapshot
+				bus.subscribers[eventType] = append(subs[:i:i], subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish delivers an event to every handler subscribed to type T
+func Publish[T any](bus *Bus, event T) {
+	if bus == nil {
+		return
+	}
+
+	bus.queue = append(bus.queue, queuedEvent{eventType: reflect.TypeFor[T](), event: event})
+	if bus.dispatching {
+		return
+	}
+
+	// Reset on exit so a panicking handler can't leave stale events queued
+	bus.dispatching = true
+	defer func() {
+		bus.dispatching = false
+		bus.queue = nil
+	}()
+
+	for len(bus.queue) > 0 {
+		next := bus.queue[0]
+		bus.queue[0] = queuedEvent{}
+		bus.queue = bus.queue[1:]
+
+		for _, sub := range bus.subscribers[next.eventType] {
+			if sub.active {
+				sub.handle(next.event)
+			}
+		}
+	}
+}
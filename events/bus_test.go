@@ -0,0 +1,93 @@
+package events
+
+import (
+	"slices"
+	"testing"
+)
+
+type ping struct{ n int }
+
+type pong struct{ n int }
+
+func TestPublishRunsHandlersInSubscriptionOrder(t *testing.T) {
+	bus := NewBus()
+	var got []string
+	Subscribe(bus, func(ping) { got = append(got, "first") })
+	Subscribe(bus, func(ping) { got = append(got, "second") })
+	Subscribe(bus, func(pong) { got = append(got, "pong") })
+	Subscribe(bus, func(ping) { got = append(got, "third") })
+
+	Publish(bus, ping{})
+
+	want := []string{"first", "second", "third"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("handlers ran as %v, want %v", got, want)
+	}
+}
+
+func TestPublishFromHandlerIsQueued(t *testing.T) {
+	bus := NewBus()
+	var got []string
+	Subscribe(bus, func(event ping) {
+		got = append(got, "ping a")
+		if event.n == 0 {
+			Publish(bus, pong{n: 1})
+			Publish(bus, ping{n: 2})
+		}
+	})
+	Subscribe(bus, func(ping) { got = append(got, "ping b") })
+	Subscribe(bus, func(pong) { got = append(got, "pong") })
+
+	Publish(bus, ping{})
+
+	// The first ping reaches every handler before the queued events, which
+	// follow in publish order
+	want := []string{"ping a", "ping b", "pong", "ping a", "ping b"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("events delivered as %v, want %v", got, want)
+	}
+}
+
+func TestUnsubscribeDuringDispatch(t *testing.T) {
+	bus := NewBus()
+	var got []string
+	var unsubscribeSecond func()
+	Subscribe(bus, func(ping) {
+		got = append(got, "first")
+		unsubscribeSecond()
+	})
+	unsubscribeSecond = Subscribe(bus, func(ping) { got = append(got, "second") })
+	Subscribe(bus, func(ping) { got = append(got, "third") })
+
+	Publish(bus, ping{})
+	Publish(bus, ping{})
+
+	want := []string{"first", "third", "first", "third"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("handlers ran as %v, want %v", got, want)
+	}
+}
+
+func TestUnsubscribeSelfDuringDispatch(t *testing.T) {
+	bus := NewBus()
+	calls := 0
+	var unsubscribe func()
+	unsubscribe = Subscribe(bus, func(ping) {
+		calls++
+		unsubscribe()
+	})
+
+	Publish(bus, ping{})
+	Publish(bus, ping{})
+
+	if calls != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls)
+	}
+}
+
+func TestNilBus(t *testing.T) {
+	var bus *Bus
+	unsubscribe := Subscribe(bus, func(ping) { t.Fatal("nil bus delivered an event") })
+	Publish(bus, ping{})
+	unsubscribe()
+}
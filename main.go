@@ -5,10 +5,15 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"math"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 
+	"flow/ecs"
+	"flow/events"
 	"flow/navigation"
 	"flow/systems"
 )
@@ -21,6 +26,12 @@ const (
 	fontSize = 24 // Font size for arrows
 )
 
+// Data file locations
+const (
+	enemyTypesPath = "data/enemies.json"
+	buildingsPath  = "data/buildings.json"
+)
+
 var (
 	// Grid dimensions
 	Width, Height = 10, 10
@@ -29,27 +40,69 @@ var (
 	windowWidth  = Width*cellSize + 2*marginX
 	windowHeight = Height*cellSize + 2*marginY
 
+	// Run the navigator on hexagons instead of squares
+	hexGrid = flag.Bool("hex", false, "use a hexagonal grid (navigation view only)")
+	// Wrap the grid around at its edges like a torus
+	wrapGrid = flag.Bool("wrap", false, "wrap the grid around at its edges")
+	// Optional JSON map replacing the built-in obstacles
+	mapPath = flag.String("map", "", "load terrain and edge costs from a JSON map file")
+	// Pixels per topology layout unit: a cell width for squares, the circumradius for hexagons
+	layoutScale = float64(cellSize)
+
+	// Event bus shared by the navigator and game systems
+	bus *events.Bus
+	// Entity world holding enemies, turrets and projectiles
+	world *ecs.World
+	// Simulation clock shared through the world
+	clock *systems.Clock
 	// Navigation system
 	navigator *navigation.FlowFieldNavigator
 	// Enemy system
 	enemySystem *systems.EnemySystem
 	// Turret system
 	turretSystem *systems.TurretSystem
+	// Projectile system
+	projectileSystem *systems.ProjectileSystem
 	// Building system
 	buildingSystem *systems.BuildingSystem
+
+	// Building catalogue and the currently selected entry
+	buildings        *systems.BuildingRegistry
+	selectedBuilding int
+	// Clockwise quarter turns applied to placed footprints
+	placementRotation int
 )
 
 func main() {
+	flag.Parse()
+
 	// Initialize navigation system
 	config := navigation.EightWayConfig(Width, Height)
+	config.Integration = navigation.FastMarching
+	if *hexGrid {
+		config = navigation.HexConfig(Width, Height)
+		layoutScale = cellSize / 2
+		windowWidth = int(math.Ceil(math.Sqrt(3)*(float64(Width)+0.5)*layoutScale)) + 2*marginX
+		windowHeight = int(math.Ceil((1.5*float64(Height)+0.5)*layoutScale)) + 2*marginY
+	}
+	config.WrapX, config.WrapY = *wrapGrid, *wrapGrid
 	var err error
 	navigator, err = navigation.NewFlowFieldNavigator(config)
 	if err != nil {
 		log.Fatal("Failed to create navigator:", err)
 	}
-
-	// Set up initial obstacles
-	setupObstacles()
+	defer navigator.Close()
+	bus = events.NewBus()
+	navigator.SetEventBus(bus)
+
+	// Set up initial obstacles, or load them from a map
+	if *mapPath != "" {
+		if err := loadMap(*mapPath); err != nil {
+			log.Fatal("Failed to load map:", err)
+		}
+	} else {
+		setupObstacles()
+	}
 
 	// Set initial goal
 	initialGoal := navigation.Position{X: 7, Y: 2}
@@ -64,6 +117,12 @@ func main() {
 	// Set target FPS for smooth rendering
 	rl.SetTargetFPS(60)
 
+	// The game systems only support square cells
+	if *hexGrid {
+		runNavigatorView()
+		return
+	}
+
 	// Initialize enemy system
 	enemyConfig := systems.Config{
 		Width:            Width,
@@ -71,7 +130,11 @@ func main() {
 		CellSize:         cellSize,
 		MarginX:          marginX,
 		MarginY:          marginY,
+		TimeStep:         1.0 / 60.0,
+		ProjectileSpeed:  8.0,
 		UnitSpeed:        2.0,
+		StartingGold:     50,
+		DangerScale:      2.0,
 		SeparationRadius: 15.0,
 		SeparationForce:  10.0,
 		AlignmentRadius:  25.0,
@@ -80,14 +143,34 @@ func main() {
 		CohesionForce:    0.2,
 		MaxSteerForce:    0.6,
 	}
-	enemySystem = systems.NewEnemySystem(navigator, enemyConfig)
-	enemySystem.SpawnEnemies(100)
+	world = ecs.NewWorld()
+	clock = systems.NewClock(world, enemyConfig.TimeStep)
+	enemySystem = systems.NewEnemySystem(world, navigator, bus, enemyConfig)
+
+	// Load enemy archetypes and spawn a mixed wave, falling back to the default type
+	enemyTypes := systems.NewEnemyTypeRegistry()
+	if err := enemyTypes.LoadFile(enemyTypesPath); err != nil {
+		log.Printf("Failed to load enemy types: %v", err)
+		enemySystem.SpawnEnemies(100)
+	} else {
+		spawnWave(enemyTypes, 100)
+	}
 
 	// Initialize turret system
-	turretSystem = systems.NewTurretSystem(enemySystem, enemyConfig)
+	turretSystem = systems.NewTurretSystem(world, enemyConfig)
+
+	// Initialize projectile system
+	projectileSystem = systems.NewProjectileSystem(world, enemyConfig)
 
 	// Initialize building system
-	buildingSystem = systems.NewBuildingSystem(navigator, turretSystem, enemyConfig)
+	buildingSystem = systems.NewBuildingSystem(world, navigator, bus, enemyConfig)
+
+	// Load the building catalogue, falling back to the built-in definitions
+	buildings = systems.NewBuildingRegistry()
+	if err := buildings.LoadFile(buildingsPath); err != nil {
+		log.Printf("Failed to load buildings: %v", err)
+		buildings = systems.DefaultBuildingRegistry()
+	}
 
 	// Main rendering loop
 	for !rl.WindowShouldClose() {
@@ -97,12 +180,21 @@ func main() {
 		// Handle keyboard input for building placement
 		handleKeyboardInput()
 
+		// Advance the simulation clock
+		clock.Tick()
+
+		// Announce flow fields recomputed in the background since last frame
+		navigator.Poll()
+
 		// Update all enemies with steering behaviors
 		enemySystem.Update()
-		
-		// Update turret system to check for enemies in range
+
+		// Update turret system to fire at enemies in range
 		turretSystem.Update()
 
+		// Move projectiles and resolve hits
+		projectileSystem.Update()
+
 		// Begin drawing phase
 		rl.BeginDrawing()
 		rl.ClearBackground(rl.RayWhite)
@@ -116,46 +208,100 @@ func main() {
 		// Draw all enemies
 		enemySystem.Draw()
 
+		// Draw projectiles in flight
+		projectileSystem.Draw()
+
+		// Draw the building selection
+		drawSelection()
+
 		// End drawing phase
 		rl.DrawFPS(10, 10)
 		rl.EndDrawing()
 	}
 }
 
-// setupObstacles creates initial obstacles in the grid
-func setupObstacles() {
-	grid := navigator.GetGrid()
-	costs := make([][]int, Height)
+// runNavigatorView runs the flow field on its own, without the game systems.
+// Left click sets the goal and right click toggles an obstacle.
+func runNavigatorView() {
+	for !rl.WindowShouldClose() {
+		if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
+			gridX, gridY := mouseGridPosition()
+			// Invalid goals (out of bounds or blocked) are ignored
+			_ = navigator.SetGoal(navigation.Position{X: gridX, Y: gridY})
+		}
 
-	// Initialize costs with current grid state
-	for y := range Height {
-		costs[y] = make([]int, Width)
-		copy(costs[y], grid.Costs[y])
+		if rl.IsMouseButtonPressed(rl.MouseRightButton) {
+			gridX, gridY := mouseGridPosition()
+			toggleObstacle(navigation.Position{X: gridX, Y: gridY})
+		}
+
+		navigator.Poll()
+
+		rl.BeginDrawing()
+		rl.ClearBackground(rl.RayWhite)
+		drawFlowField()
+		rl.DrawFPS(10, 10)
+		rl.EndDrawing()
+	}
+}
+
+// toggleObstacle switches a cell between obstacle and passable
+func toggleObstacle(cell navigation.Position) {
+	cellType := navigation.Obstacle
+	if navigator.View().CellTypeAt(cell) == navigation.Obstacle {
+		cellType = navigation.Passable
+	}
+
+	if err := navigator.SetCellType(cell, cellType); err != nil {
+		log.Printf("Failed to toggle obstacle: %v", err)
+	}
+}
+
+// spawnWave spawns count enemies split evenly across all registered types
+func spawnWave(enemyTypes *systems.EnemyTypeRegistry, count int) {
+	names := enemyTypes.Names()
+	for i, name := range names {
+		enemyType, err := enemyTypes.Get(name)
+		if err != nil {
+			continue
+		}
+
+		// Give the remainder to the first types so the total matches count
+		share := count / len(names)
+		if i < count%len(names) {
+			share++
+		}
+		enemySystem.SpawnEnemiesOfType(enemyType, share)
 	}
+}
 
+// setupObstacles creates initial obstacles in the grid
+func setupObstacles() {
 	// Add obstacles: Create a 3x3 wall from (4,4) to (6,6)
 	for x := 4; x <= 6; x++ {
 		for y := 4; y <= 6; y++ {
 			if x < Width && y < Height {
-				costs[y][x] = -1 // Mark as obstacle
-				grid.SetObstacle(navigation.Position{X: x, Y: y})
+				if err := navigator.SetCellType(navigation.Position{X: x, Y: y}, navigation.Obstacle); err != nil {
+					log.Printf("Failed to place obstacle: %v", err)
+				}
 			}
 		}
 	}
+}
 
-	// Update navigator with new costs
-	if err := navigator.UpdateCosts(costs); err != nil {
-		log.Printf("Failed to update costs: %v", err)
+// loadMap replaces the navigator's terrain and edges with a JSON map file
+func loadMap(path string) error {
+	m, err := navigation.LoadMapFile(path)
+	if err != nil {
+		return err
 	}
+	return navigator.LoadMap(m)
 }
 
-// handleMouseInput checks for mouse clicks and updates goal position
+// handleMouseInput sets the goal on left click and removes buildings on right click
 func handleMouseInput() {
 	if rl.IsMouseButtonPressed(rl.MouseLeftButton) {
-		mousePos := rl.GetMousePosition()
-
-		gridX := int((mousePos.X - float32(marginX)) / float32(cellSize))
-		gridY := int((mousePos.Y - float32(marginY)) / float32(cellSize))
+		gridX, gridY := mouseGridPosition()
 
 		newGoal := navigation.Position{X: gridX, Y: gridY}
 
@@ -165,18 +311,94 @@ func handleMouseInput() {
 			return
 		}
 	}
+
+	if rl.IsMouseButtonPressed(rl.MouseRightButton) {
+		gridX, gridY := mouseGridPosition()
+		if building, ok := buildingSystem.BuildingAt(gridX, gridY); ok {
+			buildingSystem.RemoveBuilding(building.ID)
+		}
+	}
 }
 
-// handleKeyboardInput checks for spacebar press to place buildings
+// handleKeyboardInput selects a building (number keys), places it under the
+// mouse (Space), upgrades the turret under the mouse (U) and rotates the
+// placement footprint (R)
 func handleKeyboardInput() {
-	if rl.IsKeyPressed(rl.KeySpace) {
-		mousePos := rl.GetMousePosition()
+	names := buildings.Names()
+	for i := range min(len(names), 9) {
+		if rl.IsKeyPressed(rl.KeyOne + int32(i)) {
+			selectedBuilding = i
+		}
+	}
+
+	if rl.IsKeyPressed(rl.KeySpace) && selectedBuilding < len(names) {
+		def, err := buildings.Get(names[selectedBuilding])
+		if err == nil {
+			gridX, gridY := mouseGridPosition()
+			buildingSystem.Place(def, gridX, gridY, placementRotation)
+		}
+	}
+
+	if rl.IsKeyPressed(rl.KeyU) {
+		gridX, gridY := mouseGridPosition()
+		if building, ok := buildingSystem.BuildingAt(gridX, gridY); ok && building.TurretID != 0 {
+			if err := buildingSystem.Upgrade(building.TurretID); err != nil {
+				log.Printf("Cannot upgrade turret %d: %v", building.TurretID, err)
+			}
+		}
+	}
+
+	if rl.IsKeyPressed(rl.KeyR) {
+		placementRotation = (placementRotation + 1) % 4
+	}
+}
+
+// drawSelection renders the selected building and rotation along the top margin
+func drawSelection() {
+	names := buildings.Names()
+	if selectedBuilding >= len(names) {
+		return
+	}
+
+	label := fmt.Sprintf(
+		"[%d] %s  rot %d  gold %d",
+		selectedBuilding+1, names[selectedBuilding], placementRotation*90, buildingSystem.Gold(),
+	)
+	rl.DrawText(label, int32(marginX+100), 8, int32(fontSize/2), rl.DarkGray)
+}
+
+// mouseGridPosition converts the mouse position to grid coordinates using the
+// navigator's topology
+func mouseGridPosition() (int, int) {
+	mousePos := rl.GetMousePosition()
+
+	cell := navigator.Topology().CellAt(navigation.Vector{
+		X: float64(mousePos.X-float32(marginX)) / layoutScale,
+		Y: float64(mousePos.Y-float32(marginY)) / layoutScale,
+	})
 
-		gridX := int((mousePos.X - float32(marginX)) / float32(cellSize))
-		gridY := int((mousePos.Y - float32(marginY)) / float32(cellSize))
+	return cell.X, cell.Y
+}
+
+// cellCenter returns the screen position of a cell's centre
+func cellCenter(cell navigation.Position) rl.Vector2 {
+	center := navigator.Topology().Center(cell)
+	return rl.Vector2{
+		X: float32(marginX + center.X*layoutScale),
+		Y: float32(marginY + center.Y*layoutScale),
+	}
+}
 
-		buildingSystem.PlaceBuilding(gridX, gridY)
+// drawCell fills a cell's square or hexagon
+func drawCell(cell navigation.Position, color rl.Color) {
+	if *hexGrid {
+		rl.DrawPoly(cellCenter(cell), 6, float32(layoutScale), 30, color)
+		return
 	}
+
+	cellX := int32(marginX + cell.X*cellSize)
+	cellY := int32(marginY + cell.Y*cellSize)
+	rl.DrawRectangle(cellX, cellY, int32(cellSize), int32(cellSize), color)
 }
 
 // drawFlowField renders the entire flow field grid using raylib
@@ -185,35 +407,64 @@ func drawFlowField() {
 	drawGrid()
 
 	goal := navigator.GetGoal()
-	grid := navigator.GetGrid()
+	view := navigator.View()
 
 	// Draw each cell based on its type and flow direction
 	for y := range Height {
 		for x := range Width {
 			// Calculate screen position for this grid cell
-			cellX := int32(marginX + x*cellSize)
-			cellY := int32(marginY + y*cellSize)
+			cell := navigation.Position{X: x, Y: y}
+			center := cellCenter(cell)
 
-			if grid.Costs[y][x] == -1 {
-				// Draw obstacles as black filled rectangles
-				rl.DrawRectangle(cellX, cellY, int32(cellSize), int32(cellSize), rl.Black)
+			if !view.IsPassable(cell) {
+				// Draw obstacles as black filled cells
+				drawCell(cell, rl.Black)
 			} else if x == goal.X && y == goal.Y {
-				// Draw goal as bright green rectangle
-				rl.DrawRectangle(cellX, cellY, int32(cellSize), int32(cellSize), rl.Lime)
+				// Draw goal as bright green cell
+				drawCell(cell, rl.Lime)
 				// Add "GOAL" text in center
 				textWidth := rl.MeasureText("GOAL", int32(fontSize/2))
-				rl.DrawText("GOAL", cellX+(int32(cellSize)-textWidth)/2, cellY+int32(cellSize)/2-int32(fontSize/4), int32(fontSize/2), rl.Black)
+				rl.DrawText("GOAL", int32(center.X)-textWidth/2, int32(center.Y)-int32(fontSize/4), int32(fontSize/2), rl.Black)
+			} else if view.ComponentAt(cell) != view.ComponentAt(goal) {
+				// Grey out cells walled off from the goal
+				drawCell(cell, rl.Fade(rl.Gray, 0.3))
+			} else if view.FlowAt(cell) == navigation.UsePortal {
+				// Units here take the portal instead of walking
+				rl.DrawCircleV(center, float32(cellSize)/6, rl.Purple)
 			} else {
 				// Draw flow arrow for navigable cells
-				direction := grid.FlowField[y][x]
-				drawFlowArrow(cellX, cellY, direction)
+				direction := view.FlowVectorAt(cell)
+				drawFlowArrow(center, direction)
 			}
 		}
 	}
+
+	drawPortals()
+}
+
+// drawPortals links each portal entrance to its exit
+func drawPortals() {
+	for _, portal := range navigator.Portals() {
+		from := cellCenter(portal.From)
+		to := cellCenter(portal.To)
+		rl.DrawLineV(from, to, rl.Fade(rl.Purple, 0.4))
+		rl.DrawCircleLinesV(from, float32(cellSize)/3, rl.Purple)
+		rl.DrawCircleLinesV(to, float32(cellSize)/3, rl.Violet)
+	}
 }
 
 // drawGrid renders the background grid lines for visual clarity
 func drawGrid() {
+	// Outline every hexagon
+	if *hexGrid {
+		for y := range Height {
+			for x := range Width {
+				rl.DrawPolyLines(cellCenter(navigation.Position{X: x, Y: y}), 6, float32(layoutScale), 30, rl.LightGray)
+			}
+		}
+		return
+	}
+
 	// Draw vertical grid lines
 	for x := 0; x <= Width; x++ {
 		lineX := int32(marginX + x*cellSize)
@@ -228,20 +479,17 @@ func drawGrid() {
 }
 
 // drawFlowArrow renders a directional arrow in the specified cell
-func drawFlowArrow(cellX, cellY int32, direction navigation.Direction) {
+func drawFlowArrow(center rl.Vector2, direction navigation.Vector) {
+	centerX := int32(center.X)
+	centerY := int32(center.Y)
+
 	// Skip drawing if no direction
 	if direction.X == 0 && direction.Y == 0 {
 		// Draw a dot for unreachable cells
-		centerX := cellX + int32(cellSize)/2
-		centerY := cellY + int32(cellSize)/2
 		rl.DrawCircle(centerX, centerY, 3, rl.Gray)
 		return
 	}
 
-	// Calculate center of cell
-	centerX := cellX + int32(cellSize)/2
-	centerY := cellY + int32(cellSize)/2
-
 	// Arrow dimensions
 	arrowLength := int32(cellSize / 3)
 	arrowHeadSize := int32(cellSize / 8)
@@ -0,0 +1,51 @@
+package navigation
+
+import "testing"
+
+// benchmarkSize is the width and height of the benchmark grids
+const benchmarkSize = 256
+
+// newBenchmarkNavigator returns a large 8-way navigator with a published field
+func newBenchmarkNavigator(b *testing.B) *FlowFieldNavigator {
+	b.Helper()
+	navigator := newTestNavigator(b, EightWayConfig(benchmarkSize, benchmarkSize))
+	if err := navigator.SetGoal(Position{}); err != nil {
+		b.Fatal(err)
+	}
+	navigator.Wait()
+	return navigator
+}
+
+func BenchmarkRecompute(b *testing.B) {
+	navigator := newBenchmarkNavigator(b)
+	goals := []Position{{X: benchmarkSize - 1, Y: benchmarkSize - 1}, {}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		if err := navigator.SetGoal(goals[i%len(goals)]); err != nil {
+			b.Fatal(err)
+		}
+		navigator.Wait()
+	}
+}
+
+func BenchmarkGetGrid(b *testing.B) {
+	navigator := newBenchmarkNavigator(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		_ = navigator.GetGrid()
+	}
+}
+
+func BenchmarkView(b *testing.B) {
+	navigator := newBenchmarkNavigator(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		_ = navigator.View()
+	}
+}
@@ -0,0 +1,220 @@
+package navigation
+
+import "maps"
+
+// NoComponent is the component of blocked cells and positions outside the grid
+const NoComponent = -1
+
+// ComponentAt returns the connected component of a ground cell, or NoComponent
+// if it is blocked or outside the grid
+func (f *FlowFieldNavigator) ComponentAt(pos Position) int {
+	return f.View().ComponentAt(pos)
+}
+
+// ComponentAt returns the connected component of a ground cell, or NoComponent
+// if it is blocked or outside the grid. Cells share a component when a step is
+// allowed between them in at least one direction or a portal links them, so
+// with one-way edges or portals a shared component doesn't promise a path; use
+// IsReachable for that. Labels are only meaningful compared with each other
+// within one view.
+func (v GridView) ComponentAt(pos Position) int {
+	i, ok := v.index(pos)
+	if !ok {
+		return NoComponent
+	}
+	return v.cells.components[i]
+}
+
+// IsReachable reports whether a ground unit at from can walk to to, honouring
+// edge overrides, the corner rule and portals. It answers from the component
+// labels alone unless some link only works one way, in which case it searches
+// the component.
+func (f *FlowFieldNavigator) IsReachable(from, to Position) bool {
+	cells := f.cells.Load()
+	from, to = f.wrap(from), f.wrap(to)
+	if !f.inBounds(from) || !f.inBounds(to) {
+		return false
+	}
+
+	start, end := f.index(from), f.index(to)
+	if cells.components[start] == NoComponent || cells.components[start] != cells.components[end] {
+		return false
+	}
+	if start == end || !cells.directed {
+		return true
+	}
+
+	// Walk forwards along allowed steps and portals until to turns up
+	visited := make([]bool, len(cells.costs))
+	visited[start] = true
+	queue := []int{start}
+	for head := 0; head < len(queue); head++ {
+		pos := f.position(queue[head])
+
+		var next []Position
+		for _, dir := range f.topology.Neighbors(pos) {
+			neighbor, ok := f.neighbor(pos, dir)
+			if ok && f.stepCost(cells.costs, cells.edges, pos, dir) >= 0 {
+				next = append(next, neighbor)
+			}
+		}
+		if portal, ok := cells.portals[pos]; ok {
+			next = append(next, portal.To)
+		}
+
+		for _, pos := range next {
+			cell := f.index(pos)
+			if cells.costs[cell] == -1 || visited[cell] {
+				continue
+			}
+			if cell == end {
+				return true
+			}
+			visited[cell] = true
+			queue = append(queue, cell)
+		}
+	}
+
+	return false
+}
+
+// labelComponents labels the connected components of a new snapshot. While
+// edges and portals are unchanged, only the components around cells that were
+// blocked or opened since the previous snapshot are relabelled, so placing a
+// building costs about the size of the regions it touches rather than the grid.
+func (f *FlowFieldNavigator) labelComponents(cells, previous *cellSet) {
+	cells.directed = f.isDirected(cells)
+	links := f.portalLinks(cells)
+
+	if previous == nil || !maps.Equal(cells.edges, previous.edges) || !maps.Equal(cells.portals, previous.portals) {
+		cells.components = make([]int, len(cells.costs))
+		region := make([]int, len(cells.costs))
+		for i := range region {
+			cells.components[i] = NoComponent
+			region[i] = i
+		}
+		cells.nextComponent = f.flood(cells, links, region, 0)
+		return
+	}
+
+	var changed []int
+	for i, cost := range cells.costs {
+		if (cost == -1) != (previous.costs[i] == -1) {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		cells.components, cells.nextComponent = previous.components, previous.nextComponent
+		return
+	}
+
+	// Components beside a changed cell may have split or merged, including
+	// across the diagonals the corner rule opens and closes around it
+	affected := make(map[int]bool)
+	for _, cell := range changed {
+		affected[previous.components[cell]] = true
+		pos := f.position(cell)
+		for _, dir := range f.topology.Neighbors(pos) {
+			if neighbor, ok := f.neighbor(pos, dir); ok {
+				affected[previous.components[f.index(neighbor)]] = true
+			}
+		}
+		for _, linked := range links[cell] {
+			affected[previous.components[linked]] = true
+		}
+	}
+	delete(affected, NoComponent)
+
+	cells.components = append([]int(nil), previous.components...)
+	region := changed
+	for i, component := range cells.components {
+		if affected[component] {
+			region = append(region, i)
+		}
+	}
+	for _, cell := range region {
+		cells.components[cell] = NoComponent
+	}
+	cells.nextComponent = f.flood(cells, links, region, previous.nextComponent)
+}
+
+// flood gives every open, unlabelled cell of a region a component, numbering
+// new components from next, and returns the next unused label
+func (f *FlowFieldNavigator) flood(cells *cellSet, links map[int][]int, region []int, next int) int {
+	labels := cells.components
+	var queue []int
+	for _, seed := range region {
+		if cells.costs[seed] == -1 || labels[seed] != NoComponent {
+			continue
+		}
+
+		labels[seed] = next
+		queue = append(queue[:0], seed)
+		for head := 0; head < len(queue); head++ {
+			cell := queue[head]
+			pos := f.position(cell)
+
+			for _, dir := range f.topology.Neighbors(pos) {
+				neighbor, ok := f.neighbor(pos, dir)
+				if !ok {
+					continue
+				}
+				other := f.index(neighbor)
+				if cells.costs[other] == -1 || labels[other] != NoComponent {
+					continue
+				}
+
+				// Either way across counts
+				back := Direction{X: -dir.X, Y: -dir.Y}
+				if f.stepCost(cells.costs, cells.edges, pos, dir) < 0 && f.stepCost(cells.costs, cells.edges, neighbor, back) < 0 {
+					continue
+				}
+				labels[other] = next
+				queue = append(queue, other)
+			}
+
+			for _, other := range links[cell] {
+				if labels[other] == NoComponent {
+					labels[other] = next
+					queue = append(queue, other)
+				}
+			}
+		}
+		next++
+	}
+	return next
+}
+
+// portalLinks lists the cells each open cell is joined to by portals, in
+// either direction
+func (f *FlowFieldNavigator) portalLinks(cells *cellSet) map[int][]int {
+	links := make(map[int][]int)
+	for _, portal := range cells.portals {
+		from, to := f.index(portal.From), f.index(portal.To)
+		if cells.costs[from] == -1 || cells.costs[to] == -1 {
+			continue
+		}
+		links[from] = append(links[from], to)
+		links[to] = append(links[to], from)
+	}
+	return links
+}
+
+// isDirected reports whether some edge override or portal only works one way
+func (f *FlowFieldNavigator) isDirected(cells *cellSet) bool {
+	for edge, cost := range cells.edges {
+		if cost != BlockedEdge {
+			continue
+		}
+		reverse := edge.Reverse()
+		if !isBlocked(cells.edges, f.wrap(reverse.From), reverse.Dir) {
+			return true
+		}
+	}
+	for _, portal := range cells.portals {
+		if back, ok := cells.portals[portal.To]; !ok || back.To != portal.From {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package navigation
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+// samePartition reports how two component labellings differ, or nil if they
+// block the same cells and group the open ones identically
+func samePartition(got, want []int) error {
+	forward, backward := make(map[int]int), make(map[int]int)
+	for i := range want {
+		if (got[i] == NoComponent) != (want[i] == NoComponent) {
+			return fmt.Errorf("cell %d labelled %d, want %d", i, got[i], want[i])
+		}
+		if want[i] == NoComponent {
+			continue
+		}
+		if label, ok := forward[got[i]]; ok && label != want[i] {
+			return fmt.Errorf("cell %d joins component %d to both %d and %d", i, got[i], label, want[i])
+		}
+		if label, ok := backward[want[i]]; ok && label != got[i] {
+			return fmt.Errorf("cell %d splits component %d into %d and %d", i, want[i], label, got[i])
+		}
+		forward[got[i]], backward[want[i]] = want[i], got[i]
+	}
+	return nil
+}
+
+func TestIncrementalComponentsMatchFullRelabel(t *testing.T) {
+	eightWay := EightWayConfig(12, 12)
+	eightWay.Levels = 2
+	noCorners := EightWayConfig(12, 12)
+	noCorners.AllowCornerCutting = false
+	noCorners.WrapX, noCorners.WrapY = true, true
+
+	configs := map[string]Config{
+		"eight-way":  eightWay,
+		"hex":        HexConfig(12, 12),
+		"no-corners": noCorners,
+	}
+
+	for name, config := range configs {
+		t.Run(name, func(t *testing.T) {
+			navigator := newTestNavigator(t, config)
+			levels := config.levels()
+			if levels > 1 {
+				if err := navigator.AddStairs(Position{X: 2, Y: 2}, Position{X: 9, Y: 9, Level: 1}, 1); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := navigator.SetOneWay(Position{X: 5, Y: 5}, Direction{X: 1}); err != nil {
+				t.Fatal(err)
+			}
+
+			rng := rand.New(rand.NewPCG(1, uint64(len(name))))
+			for step := range 300 {
+				pos := Position{X: rng.IntN(12), Y: rng.IntN(12), Level: rng.IntN(levels)}
+				cellType := Obstacle
+				if rng.IntN(3) == 0 {
+					cellType = Passable
+				}
+				if err := navigator.SetCellType(pos, cellType); err != nil {
+					t.Fatal(err)
+				}
+
+				cells := navigator.cells.Load()
+				full := &cellSet{costs: cells.costs, edges: cells.edges, portals: cells.portals}
+				navigator.labelComponents(full, nil)
+				if err := samePartition(cells.components, full.components); err != nil {
+					t.Fatalf("step %d, %v set to %v: %v", step, pos, cellType, err)
+				}
+			}
+		})
+	}
+}
+
+func TestIsReachableOneWayEdge(t *testing.T) {
+	navigator := newCorridor(t, 3)
+	start, end := Position{X: 0}, Position{X: 2}
+	if err := navigator.SetOneWay(start, Direction{X: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if navigator.ComponentAt(start) != navigator.ComponentAt(end) {
+		t.Fatal("a one-way edge split the component")
+	}
+	if !navigator.IsReachable(start, end) {
+		t.Error("cannot walk along the one-way edge")
+	}
+	if navigator.IsReachable(end, start) {
+		t.Error("walked back against the one-way edge")
+	}
+}
+
+func TestIsReachableOneWayPortal(t *testing.T) {
+	navigator := newCorridor(t, 5)
+	if err := navigator.SetCellType(Position{X: 2}, Obstacle); err != nil {
+		t.Fatal(err)
+	}
+	start, end := Position{X: 0}, Position{X: 4}
+	if navigator.IsReachable(start, end) {
+		t.Fatal("reached across the wall without a portal")
+	}
+
+	if err := navigator.AddPortal(Portal{From: Position{X: 1}, To: Position{X: 3}, Cost: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if navigator.ComponentAt(start) != navigator.ComponentAt(end) {
+		t.Fatal("the portal didn't join the two sides")
+	}
+	if !navigator.IsReachable(start, end) {
+		t.Error("cannot reach the far side through the portal")
+	}
+	if navigator.IsReachable(end, start) {
+		t.Error("walked back through a one-way portal")
+	}
+}
@@ -1,6 +1,25 @@
 package navigation
 
-import "errors"
+import (
+	"errors"
+	"math"
+)
+
+// Sqrt2Diagonal is the exact cost multiplier for a diagonal step on a square grid
+const Sqrt2Diagonal = math.Sqrt2
+
+// IntegrationMethod selects how distances to the goal are computed
+type IntegrationMethod int
+
+const (
+	// Dijkstra sums step costs over the configured directions. It is exact on
+	// the grid graph but shows banding along angles between those directions.
+	Dijkstra IntegrationMethod = iota
+	// FastMarching solves the eikonal equation for near-Euclidean distances
+	// that give natural-looking crowd movement. Grids with edge overrides fall
+	// back to Dijkstra.
+	FastMarching
+)
 
 // Config contains configuration for the navigation system
 type Config struct {
@@ -8,14 +27,42 @@ type Config struct {
 	GridWidth  int
 	GridHeight int
 
-	// Movement directions (4-way or 8-way)
+	// Stacked floors connected by stairs; 0 means a single level
+	Levels int
+
+	// Whether the grid wraps around at its left/right and top/bottom edges,
+	// making neighbours across each edge adjacent
+	WrapX, WrapY bool
+
+	// Movement directions (4-way or 8-way) for square grids
 	Directions []Direction
 
-	// Cost multiplier for diagonal movements (typically sqrt(2) ≈ 1.4)
+	// Cost multiplier for diagonal movements, applied to fractional distances
+	// without rounding (Sqrt2Diagonal for true Euclidean steps)
 	DiagonalCost float64
 
-	// Whether to allow diagonal movement through corners
+	// Cell connectivity; nil means square cells using Directions and DiagonalCost
+	Topology Topology
+
+	// Whether diagonal steps on square cells may squeeze past a blocked cell
+	// beside them; without it both cells beside a diagonal step must be open
 	AllowCornerCutting bool
+
+	// Overlay cost layers composed over the terrain, bottom to top
+	Layers []LayerSpec
+
+	// How distances to the goal are integrated; defaults to Dijkstra
+	Integration IntegrationMethod
+}
+
+// DefaultLayers returns the standard building, ground and air danger, and slow overlays
+func DefaultLayers() []LayerSpec {
+	return []LayerSpec{
+		{Name: LayerBuildings, Mode: CombineMax, Enabled: true},
+		{Name: LayerDangerGround, Mode: CombineAdd, Enabled: false},
+		{Name: LayerDangerAir, Mode: CombineAdd, Enabled: false},
+		{Name: LayerSlow, Mode: CombineAdd, Enabled: true},
+	}
 }
 
 // EightWayConfig returns a configuration for 8-way movement
@@ -24,25 +71,79 @@ func EightWayConfig(width, height int) Config {
 		GridWidth:          width,
 		GridHeight:         height,
 		Directions:         EightWayDirections,
-		DiagonalCost:       1.4,
+		DiagonalCost:       Sqrt2Diagonal,
 		AllowCornerCutting: true,
+		Layers:             DefaultLayers(),
+	}
+}
+
+// HexConfig returns a configuration for a grid of pointy-top hexagons
+func HexConfig(width, height int) Config {
+	return Config{
+		GridWidth:  width,
+		GridHeight: height,
+		Topology:   HexTopology{},
+		Layers:     DefaultLayers(),
 	}
 }
 
+// levels returns the number of grid levels, at least one
+func (c Config) levels() int {
+	return max(c.Levels, 1)
+}
+
+// topology returns the configured topology, defaulting to square cells
+func (c Config) topology() Topology {
+	if c.Topology != nil {
+		return c.Topology
+	}
+	return SquareTopology{Directions: c.Directions, DiagonalCost: c.DiagonalCost}
+}
+
 // Validate checks if the configuration is valid
 func (c Config) Validate() error {
 	if c.GridWidth <= 0 || c.GridHeight <= 0 {
 		return errors.New("grid dimensions must be positive")
 	}
 
-	if len(c.Directions) == 0 {
-		return errors.New("must have at least one direction")
+	if c.Levels < 0 {
+		return errors.New("level count must not be negative")
+	}
+
+	if c.Topology == nil {
+		if len(c.Directions) == 0 {
+			return errors.New("must have at least one direction")
+		}
+
+		if c.DiagonalCost <= 0 {
+			return errors.New("diagonal cost must be positive")
+		}
 	}
 
-	if c.DiagonalCost <= 0 {
-		return errors.New("diagonal cost must be positive")
+	if c.Integration != Dijkstra && c.Integration != FastMarching {
+		return errors.New("unknown integration method")
+	}
+
+	// Odd-r hexagon rows only line up across the top and bottom edges in pairs
+	if _, hex := c.topology().(HexTopology); hex && c.WrapY && c.GridHeight%2 != 0 {
+		return errors.New("wrapping hexagons vertically requires an even grid height")
+	}
+
+	// The eikonal update assumes square cells
+	if _, square := c.topology().(SquareTopology); c.Integration == FastMarching && !square {
+		return errors.New("fast marching requires a square topology")
+	}
+
+	seen := make(map[string]bool, len(c.Layers))
+	for _, layer := range c.Layers {
+		if layer.Name == "" {
+			return errors.New("cost layer name must not be empty")
+		}
+		if seen[layer.Name] {
+			return ErrLayerExists
+		}
+		seen[layer.Name] = true
 	}
 
 	return nil
 }
-
@@ -0,0 +1,110 @@
+package navigation
+
+import "math"
+
+// DistanceToGoal returns the path cost from a position to the goal in the
+// published ground field
+func (f *FlowFieldNavigator) DistanceToGoal(pos Position) (float64, error) {
+	field := f.field.Load()
+	if !field.isGoalSet {
+		return 0, ErrInvalidGoal
+	}
+
+	return f.lookupDistance(field.flowData, pos)
+}
+
+// NormalizedProgress returns how far along the ground field a position is,
+// from 0 at the start of the longest path to the goal to 1 at the goal
+func (f *FlowFieldNavigator) NormalizedProgress(pos Position) (float64, error) {
+	field := f.field.Load()
+	if !field.isGoalSet {
+		return 0, ErrInvalidGoal
+	}
+
+	return f.lookupProgress(field.flowData, pos)
+}
+
+// ProfileDistanceToGoal returns the path cost from a position to the goal in a
+// profile's published field
+func (f *FlowFieldNavigator) ProfileDistanceToGoal(name string, pos Position) (float64, error) {
+	data, err := f.profileData(name)
+	if err != nil {
+		return 0, err
+	}
+	return f.lookupDistance(data, pos)
+}
+
+// ProfileNormalizedProgress returns how far along a profile's field a position is
+func (f *FlowFieldNavigator) ProfileNormalizedProgress(name string, pos Position) (float64, error) {
+	data, err := f.profileData(name)
+	if err != nil {
+		return 0, err
+	}
+	return f.lookupProgress(data, pos)
+}
+
+// MovementDistanceToGoal returns the path cost from a position to the goal for a movement layer
+func (f *FlowFieldNavigator) MovementDistanceToGoal(movement MovementLayer, pos Position) (float64, error) {
+	switch movement {
+	case Ground:
+		return f.DistanceToGoal(pos)
+	case Air:
+		return f.ProfileDistanceToGoal(AirProfile, pos)
+	default:
+		return 0, ErrInvalidMovementLayer
+	}
+}
+
+// MovementNormalizedProgress returns how far along a movement layer's field a position is
+func (f *FlowFieldNavigator) MovementNormalizedProgress(movement MovementLayer, pos Position) (float64, error) {
+	switch movement {
+	case Ground:
+		return f.NormalizedProgress(pos)
+	case Air:
+		return f.ProfileNormalizedProgress(AirProfile, pos)
+	default:
+		return 0, ErrInvalidMovementLayer
+	}
+}
+
+// profileData returns a profile's published field
+func (f *FlowFieldNavigator) profileData(name string) (flowData, error) {
+	field := f.field.Load()
+	if !field.isGoalSet {
+		return flowData{}, ErrInvalidGoal
+	}
+
+	profile, ok := field.profiles[name]
+	if !ok {
+		return flowData{}, ErrProfileNotFound
+	}
+	return profile.flowData, nil
+}
+
+// lookupDistance reads the distance for a position from a published field
+func (f *FlowFieldNavigator) lookupDistance(data flowData, pos Position) (float64, error) {
+	pos = f.wrap(pos)
+	if !f.inBounds(pos) {
+		return 0, ErrInvalidPosition
+	}
+
+	distance := data.distances[f.index(pos)]
+	if math.IsInf(distance, 1) {
+		return 0, ErrNoPath
+	}
+	return distance, nil
+}
+
+// lookupProgress reads the normalised progress for a position from a published field
+func (f *FlowFieldNavigator) lookupProgress(data flowData, pos Position) (float64, error) {
+	distance, err := f.lookupDistance(data, pos)
+	if err != nil {
+		return 0, err
+	}
+
+	// Every reachable cell is the goal
+	if data.maxDistance == 0 {
+		return 1, nil
+	}
+	return 1 - distance/data.maxDistance, nil
+}
@@ -0,0 +1,78 @@
+package navigation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizedProgress(t *testing.T) {
+	navigator := newCorridor(t, 5)
+	navigator.Wait()
+
+	for x, want := range []float64{1, 0.75, 0.5, 0.25, 0} {
+		pos := Position{X: x}
+		if progress, err := navigator.NormalizedProgress(pos); err != nil || progress != want {
+			t.Errorf("progress at %v = %v, %v; want %v", pos, progress, err, want)
+		}
+	}
+	if distance, err := navigator.DistanceToGoal(Position{X: 3}); err != nil || distance != 3 {
+		t.Errorf("distance = %v, %v; want 3", distance, err)
+	}
+}
+
+func TestNormalizedProgressUnreachable(t *testing.T) {
+	navigator := newCorridor(t, 5)
+	if err := navigator.SetCellType(Position{X: 2}, Obstacle); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+
+	for _, pos := range []Position{{X: 2}, {X: 4}} {
+		if _, err := navigator.DistanceToGoal(pos); !errors.Is(err, ErrNoPath) {
+			t.Errorf("distance at %v: err = %v, want ErrNoPath", pos, err)
+		}
+		if _, err := navigator.NormalizedProgress(pos); !errors.Is(err, ErrNoPath) {
+			t.Errorf("progress at %v: err = %v, want ErrNoPath", pos, err)
+		}
+	}
+
+	// Unreachable cells don't stretch the scale for the reachable ones
+	if progress, err := navigator.NormalizedProgress(Position{X: 1}); err != nil || progress != 0 {
+		t.Errorf("progress at the far reachable cell = %v, %v; want 0", progress, err)
+	}
+}
+
+func TestNormalizedProgressOnlyGoalReachable(t *testing.T) {
+	navigator := newCorridor(t, 3)
+	if err := navigator.SetCellType(Position{X: 1}, Obstacle); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+
+	// The longest path has length zero, which must not divide into NaN
+	progress, err := navigator.NormalizedProgress(Position{})
+	if err != nil || progress != 1 {
+		t.Errorf("progress at the goal = %v, %v; want 1", progress, err)
+	}
+}
+
+func TestDistanceToGoalErrors(t *testing.T) {
+	navigator := newTestNavigator(t, EightWayConfig(3, 3))
+	if _, err := navigator.DistanceToGoal(Position{}); !errors.Is(err, ErrInvalidGoal) {
+		t.Errorf("without a goal: err = %v, want ErrInvalidGoal", err)
+	}
+
+	if err := navigator.SetGoal(Position{}); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+	if _, err := navigator.NormalizedProgress(Position{X: 3}); !errors.Is(err, ErrInvalidPosition) {
+		t.Errorf("outside the grid: err = %v, want ErrInvalidPosition", err)
+	}
+	if _, err := navigator.ProfileDistanceToGoal("missing", Position{}); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("unknown profile: err = %v, want ErrProfileNotFound", err)
+	}
+	if _, err := navigator.MovementDistanceToGoal(MovementLayer(99), Position{}); !errors.Is(err, ErrInvalidMovementLayer) {
+		t.Errorf("unknown movement layer: err = %v, want ErrInvalidMovementLayer", err)
+	}
+}
@@ -0,0 +1,166 @@
+package navigation
+
+// BlockedEdge is the edge cost that forbids a step
+const BlockedEdge = -1
+
+// Edge is a single step between neighbouring cells: leaving From while moving
+// in Dir. Edges are directed, so the reverse step is a separate edge.
+type Edge struct {
+	From Position
+	Dir  Direction
+}
+
+// To returns the cell the step enters
+func (e Edge) To() Position {
+	return e.From.step(e.Dir)
+}
+
+// Reverse returns the step back from To into From
+func (e Edge) Reverse() Edge {
+	return Edge{From: e.To(), Dir: Direction{X: -e.Dir.X, Y: -e.Dir.Y}}
+}
+
+// SetEdgeCost overrides the cost of one step. Without an override a step costs
+// the departing cell's effective cost; BlockedEdge forbids the step entirely.
+// Blocked cells stay blocked whatever their edges say.
+func (g *Grid) SetEdgeCost(edge Edge, cost int) error {
+	if !g.IsValidPosition(edge.From) || !g.IsValidPosition(edge.To()) {
+		return ErrInvalidPosition
+	}
+	if edge.Dir == (Direction{}) || edge.Dir.X < -1 || edge.Dir.X > 1 || edge.Dir.Y < -1 || edge.Dir.Y > 1 {
+		return ErrInvalidDirection
+	}
+	if cost < BlockedEdge {
+		return ErrInvalidCost
+	}
+
+	edge.From = g.Wrap(edge.From)
+	if g.edges == nil {
+		g.edges = make(map[Edge]int)
+	}
+	g.edges[edge] = cost
+	return nil
+}
+
+// SetOneWay allows the step from a cell in dir but forbids the step back
+func (g *Grid) SetOneWay(from Position, dir Direction) error {
+	return g.SetEdgeCost(Edge{From: from, Dir: dir}.Reverse(), BlockedEdge)
+}
+
+// ClearEdgeCost removes an edge override so the step costs its departing cell again
+func (g *Grid) ClearEdgeCost(edge Edge) {
+	edge.From = g.Wrap(edge.From)
+	delete(g.edges, edge)
+}
+
+// ClearEdges removes every edge override
+func (g *Grid) ClearEdges() {
+	g.edges = nil
+}
+
+// EdgeCost returns the override for a step, if there is one
+func (g *Grid) EdgeCost(edge Edge) (int, bool) {
+	edge.From = g.Wrap(edge.From)
+	cost, ok := g.edges[edge]
+	return cost, ok
+}
+
+// Edges returns a copy of every edge override
+func (g *Grid) Edges() map[Edge]int {
+	edges := make(map[Edge]int, len(g.edges))
+	for edge, cost := range g.edges {
+		edges[edge] = cost
+	}
+	return edges
+}
+
+// SetEdgeCost overrides the cost of one step and schedules a recompute. The
+// step must be one of the topology's neighbour steps from edge.From.
+func (f *FlowFieldNavigator) SetEdgeCost(edge Edge, cost int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.setEdgeCostLocked(edge, cost); err != nil {
+		return err
+	}
+	f.publishCellsLocked()
+	return f.recomputeLocked()
+}
+
+// SetOneWay allows the step from a cell in dir but forbids the step back, then
+// schedules a recompute
+func (f *FlowFieldNavigator) SetOneWay(from Position, dir Direction) error {
+	return f.SetEdgeCost(Edge{From: from, Dir: dir}.Reverse(), BlockedEdge)
+}
+
+// ClearEdgeCost removes an edge override and schedules a recompute
+func (f *FlowFieldNavigator) ClearEdgeCost(edge Edge) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.grid.ClearEdgeCost(edge)
+	f.publishCellsLocked()
+	return f.recomputeLocked()
+}
+
+// setEdgeCostLocked checks the step against the topology before overriding it.
+// The caller must hold mu.
+func (f *FlowFieldNavigator) setEdgeCostLocked(edge Edge, cost int) error {
+	if !f.isStep(edge) {
+		return ErrInvalidDirection
+	}
+	return f.grid.SetEdgeCost(edge, cost)
+}
+
+// isStep reports whether an edge's direction is a neighbour step of its cell
+func (f *FlowFieldNavigator) isStep(edge Edge) bool {
+	for _, dir := range f.topology.Neighbors(edge.From) {
+		if dir == edge.Dir {
+			return true
+		}
+	}
+	return false
+}
+
+// stepCost returns the cost of moving from a cell in dir, or -1 if the step
+// is blocked by the departing cell, by an edge override or by the corner rule
+func (f *FlowFieldNavigator) stepCost(costs []int, edges map[Edge]int, from Position, dir Direction) float64 {
+	cost := costs[f.index(from)]
+	if cost == -1 || f.cutsCorner(costs, from, dir) {
+		return -1
+	}
+
+	if override, ok := edges[Edge{From: from, Dir: dir}]; ok {
+		if override == BlockedEdge {
+			return -1
+		}
+		cost = override
+	}
+
+	// Apply the step's cost multiplier, e.g. for diagonals
+	return float64(cost) * f.topology.StepCost(dir)
+}
+
+// isBlocked reports whether an edge override forbids a step
+func isBlocked(edges map[Edge]int, from Position, dir Direction) bool {
+	cost, ok := edges[Edge{From: from, Dir: dir}]
+	return ok && cost == BlockedEdge
+}
+
+// cutsCorner reports whether a diagonal step between square cells squeezes
+// past a blocked cell beside it while corner cutting is off
+func (f *FlowFieldNavigator) cutsCorner(costs []int, from Position, dir Direction) bool {
+	if f.config.AllowCornerCutting || dir.X == 0 || dir.Y == 0 {
+		return false
+	}
+	if _, square := f.topology.(SquareTopology); !square {
+		return false
+	}
+
+	side, ok := f.neighbor(from, Direction{X: dir.X})
+	if !ok || costs[f.index(side)] == -1 {
+		return true
+	}
+	side, ok = f.neighbor(from, Direction{Y: dir.Y})
+	return !ok || costs[f.index(side)] == -1
+}
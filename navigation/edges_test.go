@@ -0,0 +1,104 @@
+package navigation
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// newCorridor returns a 4-way navigator one row high with its goal at the left end
+func newCorridor(t *testing.T, width int) *FlowFieldNavigator {
+	t.Helper()
+	navigator := newTestNavigator(t, Config{
+		GridWidth:    width,
+		GridHeight:   1,
+		Directions:   FourWayDirections,
+		DiagonalCost: 1,
+	})
+	if err := navigator.SetGoal(Position{}); err != nil {
+		t.Fatal(err)
+	}
+	return navigator
+}
+
+func TestSetEdgeCostChangesDistances(t *testing.T) {
+	navigator := newCorridor(t, 3)
+	left := Direction{X: -1}
+	if err := navigator.SetEdgeCost(Edge{From: Position{X: 1}, Dir: left}, 5); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+
+	if distance := navigator.DistanceAt(Position{X: 1}); distance != 5 {
+		t.Errorf("distance over the costly edge = %v, want 5", distance)
+	}
+	if distance := navigator.DistanceAt(Position{X: 2}); distance != 6 {
+		t.Errorf("distance beyond the costly edge = %v, want 6", distance)
+	}
+
+	// The override only applies in the direction it was set
+	if err := navigator.SetGoal(Position{X: 2}); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+	if distance := navigator.DistanceAt(Position{}); distance != 2 {
+		t.Errorf("distance against the override = %v, want 2", distance)
+	}
+}
+
+func TestSetOneWayBlocksReverse(t *testing.T) {
+	navigator := newCorridor(t, 3)
+	if err := navigator.SetOneWay(Position{}, Direction{X: 1}); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+
+	for _, pos := range []Position{{X: 1}, {X: 2}} {
+		if distance := navigator.DistanceAt(pos); !math.IsInf(distance, 1) {
+			t.Errorf("distance at %v against the one-way edge = %v, want +Inf", pos, distance)
+		}
+		if _, err := navigator.GetFlowDirection(pos); !errors.Is(err, ErrNoPath) {
+			t.Errorf("flow at %v: err = %v, want ErrNoPath", pos, err)
+		}
+	}
+
+	if err := navigator.SetGoal(Position{X: 2}); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+	if direction, err := navigator.GetFlowDirection(Position{}); err != nil || direction != (Direction{X: 1}) {
+		t.Errorf("flow with the one-way edge = %v, %v; want right", direction, err)
+	}
+
+	// Clearing the override opens the way back
+	if err := navigator.ClearEdgeCost(Edge{From: Position{X: 1}, Dir: Direction{X: -1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := navigator.SetGoal(Position{}); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+	if distance := navigator.DistanceAt(Position{X: 2}); distance != 2 {
+		t.Errorf("distance after clearing = %v, want 2", distance)
+	}
+}
+
+func TestSetEdgeCostErrors(t *testing.T) {
+	navigator := newCorridor(t, 3)
+	tests := []struct {
+		name string
+		edge Edge
+		cost int
+		err  error
+	}{
+		{"diagonal on a 4-way grid", Edge{From: Position{}, Dir: Direction{X: 1, Y: 1}}, 1, ErrInvalidDirection},
+		{"leaves the grid", Edge{From: Position{}, Dir: Direction{X: -1}}, 1, ErrInvalidPosition},
+		{"cost below blocked", Edge{From: Position{}, Dir: Direction{X: 1}}, -2, ErrInvalidCost},
+	}
+
+	for _, test := range tests {
+		if err := navigator.SetEdgeCost(test.edge, test.cost); !errors.Is(err, test.err) {
+			t.Errorf("%s: err = %v, want %v", test.name, err, test.err)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package navigation
+
+import (
+	"container/heap"
+	"math"
+)
+
+// fastMarch solves the eikonal equation |∇T| = cost outward from goal with the
+// Fast Marching Method, treating each cell's cost as its slowness. Unlike
+// Dijkstra on a grid graph, the upwind update lets fronts travel at any angle,
+// so distances stay close to Euclidean instead of favouring 45° steps. Only
+// the goal's level is marched, since other levels are reached through links.
+func (f *FlowFieldNavigator) fastMarch(goal Position, costs []int) []float64 {
+	width, height := f.config.GridWidth, f.config.GridHeight
+	all := make([]float64, len(costs))
+	for i := range all {
+		all[i] = math.Inf(1)
+	}
+
+	// Work on the goal level's slab of the flat arrays
+	base := f.index(Position{Level: goal.Level})
+	distances := all[base : base+width*height]
+	costs = costs[base : base+width*height]
+	accepted := make([]bool, width*height)
+
+	start := goal.Y*width + goal.X
+	distances[start] = 0
+	queue := &distanceQueue{{index: start}}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(distanceItem)
+		if accepted[item.index] {
+			continue // Stale entry superseded by a shorter distance
+		}
+		accepted[item.index] = true
+
+		pos := Position{X: item.index % width, Y: item.index / width}
+		for _, dir := range FourWayDirections {
+			nextPos, ok := f.neighbor(pos, dir)
+			if !ok {
+				continue
+			}
+			next := nextPos.Y*width + nextPos.X
+			if accepted[next] || costs[next] == -1 {
+				continue
+			}
+
+			if distance := f.eikonalUpdate(distances, accepted, nextPos, float64(costs[next])); distance < distances[next] {
+				distances[next] = distance
+				heap.Push(queue, distanceItem{index: next, distance: distance})
+			}
+		}
+	}
+
+	return all
+}
+
+// eikonalUpdate returns the first-order upwind solution for a cell's arrival
+// time from its accepted horizontal and vertical neighbours, reading one
+// level's slab of distances
+func (f *FlowFieldNavigator) eikonalUpdate(distances []float64, accepted []bool, pos Position, cost float64) float64 {
+	known := func(dir Direction) float64 {
+		neighbor, ok := f.neighbor(pos, dir)
+		i := neighbor.Y*f.config.GridWidth + neighbor.X
+		if !ok || !accepted[i] {
+			return math.Inf(1)
+		}
+		return distances[i]
+	}
+
+	a := min(known(Direction{X: -1}), known(Direction{X: 1}))
+	b := min(known(Direction{Y: -1}), known(Direction{Y: 1}))
+	if a > b {
+		a, b = b, a
+	}
+
+	// Only one axis is known, or the front arrives almost along an axis
+	if math.IsInf(b, 1) || b-a >= cost {
+		return a + cost
+	}
+	return (a + b + math.Sqrt(2*cost*cost-(b-a)*(b-a))) / 2
+}
+
+// flowVectors returns the normalised descent direction of the distance field
+// at each cell. Square grids without edge overrides use upwind differences
+// along each axis; otherwise the direction to each lower neighbour that may be
+// stepped into is weighted by how steeply the distance drops. Either way
+// vectors never point into blocked or unreachable cells, and cells without a
+// usable gradient fall back to their discrete flow direction. The start cell
+// gets no vector.
+func (f *FlowFieldNavigator) flowVectors(start int, edges map[Edge]int, distances []float64, flowField []Direction) []Vector {
+	vectors := make([]Vector, len(distances))
+	_, square := f.topology.(SquareTopology)
+	upwind := square && len(edges) == 0
+
+	// at reads the distance of a cell's neighbour on the same level
+	at := func(pos Position, dir Direction) float64 {
+		neighbor, ok := f.neighbor(pos, dir)
+		if !ok {
+			return math.Inf(1)
+		}
+		return distances[f.index(neighbor)]
+	}
+
+	for cell, distance := range distances {
+		if cell == start || math.IsInf(distance, 1) || flowField[cell] == UsePortal {
+			continue
+		}
+
+		pos := f.position(cell)
+		var vector Vector
+		if upwind {
+			vector = Vector{
+				X: descent(distance, at(pos, Direction{X: -1}), at(pos, Direction{X: 1})),
+				Y: descent(distance, at(pos, Direction{Y: -1}), at(pos, Direction{Y: 1})),
+			}
+		} else {
+			for _, dir := range f.topology.Neighbors(pos) {
+				neighbor := at(pos, dir)
+				if neighbor >= distance || isBlocked(edges, pos, dir) {
+					continue
+				}
+				step := f.stepVector(pos, dir)
+				vector.X += step.X * (distance - neighbor)
+				vector.Y += step.Y * (distance - neighbor)
+			}
+		}
+		if vector.X == 0 && vector.Y == 0 {
+			vector = f.stepVector(pos, flowField[cell])
+		}
+		vectors[cell] = vector.Normalized()
+	}
+
+	return vectors
+}
+
+// stepVector returns the unit spatial direction of a step from a cell
+func (f *FlowFieldNavigator) stepVector(pos Position, dir Direction) Vector {
+	from := f.topology.Center(pos)
+	to := f.topology.Center(pos.step(dir))
+	return Vector{X: to.X - from.X, Y: to.Y - from.Y}.Normalized()
+}
+
+// descent returns the signed rate of descent along one axis: negative toward
+// the lower neighbour, positive toward the higher one, 0 if neither is lower
+func descent(distance, lower, higher float64) float64 {
+	switch {
+	case lower < distance && lower <= higher:
+		return -(distance - lower)
+	case higher < distance:
+		return distance - higher
+	default:
+		return 0
+	}
+}
+
+// distanceItem is a cell waiting in the fast marching front
+type distanceItem struct {
+	index    int
+	distance float64
+}
+
+// distanceQueue is a min-heap of cells ordered by tentative distance
+type distanceQueue []distanceItem
+
+func (q distanceQueue) Len() int           { return len(q) }
+func (q distanceQueue) Less(i, j int) bool { return q[i].distance < q[j].distance }
+func (q distanceQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *distanceQueue) Push(x any)        { *q = append(*q, x.(distanceItem)) }
+func (q *distanceQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
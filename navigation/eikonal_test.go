@@ -0,0 +1,89 @@
+package navigation
+
+import (
+	"math"
+	"testing"
+)
+
+// newIntegratedPair returns a Dijkstra and a Fast Marching navigator over the
+// same grid, with setup applied to both before the goal is set
+func newIntegratedPair(t *testing.T, config Config, goal Position, setup func(*FlowFieldNavigator) error) (dijkstra, marching *FlowFieldNavigator) {
+	t.Helper()
+	navigators := make([]*FlowFieldNavigator, 2)
+	for i, method := range []IntegrationMethod{Dijkstra, FastMarching} {
+		config.Integration = method
+		navigators[i] = newTestNavigator(t, config)
+		if err := setup(navigators[i]); err != nil {
+			t.Fatal(err)
+		}
+		if err := navigators[i].SetGoal(goal); err != nil {
+			t.Fatal(err)
+		}
+		navigators[i].Wait()
+	}
+	return navigators[0], navigators[1]
+}
+
+func TestFastMarchingNearEuclidean(t *testing.T) {
+	noSetup := func(*FlowFieldNavigator) error { return nil }
+	dijkstra, marching := newIntegratedPair(t, EightWayConfig(41, 41), Position{}, noSetup)
+
+	// 22.5° is halfway between the axis and the diagonal, where 8-way steps
+	// overshoot the most
+	pos := Position{X: 29, Y: 12}
+	euclidean := math.Hypot(float64(pos.X), float64(pos.Y))
+	relativeError := func(navigator *FlowFieldNavigator) float64 {
+		return (navigator.DistanceAt(pos) - euclidean) / euclidean
+	}
+
+	if err := relativeError(dijkstra); err < 0.07 {
+		t.Fatalf("Dijkstra error at 22.5° = %.3f, expected the 8-way overshoot", err)
+	}
+	if err := relativeError(marching); math.Abs(err) > 0.03 {
+		t.Errorf("fast marching error at 22.5° = %.3f, want within 0.03", err)
+	}
+
+	// Both are exact along an axis
+	axis := Position{X: 40}
+	if dijkstra.DistanceAt(axis) != 40 || marching.DistanceAt(axis) != 40 {
+		t.Errorf("distance along the axis = %v and %v, want 40", dijkstra.DistanceAt(axis), marching.DistanceAt(axis))
+	}
+}
+
+func TestFastMarchingFallsBackToDijkstra(t *testing.T) {
+	tests := map[string]func(*FlowFieldNavigator) error{
+		"edges": func(navigator *FlowFieldNavigator) error {
+			return navigator.SetEdgeCost(Edge{From: Position{X: 4, Y: 4}, Dir: Direction{X: -1}}, 9)
+		},
+		"portals": func(navigator *FlowFieldNavigator) error {
+			return navigator.AddPortal(Portal{From: Position{X: 9, Y: 0}, To: Position{X: 1, Y: 9}, Cost: 2})
+		},
+	}
+
+	for name, setup := range tests {
+		t.Run(name, func(t *testing.T) {
+			dijkstra, marching := newIntegratedPair(t, EightWayConfig(10, 10), Position{}, setup)
+			for y := range 10 {
+				for x := range 10 {
+					pos := Position{X: x, Y: y}
+					if got, want := marching.DistanceAt(pos), dijkstra.DistanceAt(pos); got != want {
+						t.Errorf("distance at %v = %v, want Dijkstra's %v", pos, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRejectsFastMarchingOnHex(t *testing.T) {
+	config := HexConfig(8, 8)
+	config.Integration = FastMarching
+	if err := config.Validate(); err == nil {
+		t.Error("fast marching on a hex grid was accepted")
+	}
+
+	config.Integration = Dijkstra
+	if err := config.Validate(); err != nil {
+		t.Errorf("Dijkstra on a hex grid: %v", err)
+	}
+}
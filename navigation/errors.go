@@ -10,4 +10,15 @@ var (
 	ErrInvalidGoal      = errors.New("goal position is invalid or blocked")
 	ErrEmptyGrid        = errors.New("grid is empty or not initialized")
 	ErrInvalidDirection = errors.New("invalid direction")
-)
\ No newline at end of file
+	ErrLayerExists      = errors.New("cost layer already exists")
+	ErrLayerNotFound    = errors.New("cost layer not found")
+	ErrProfileExists    = errors.New("flow field profile already exists")
+	ErrProfileNotFound  = errors.New("flow field profile not found")
+	ErrInvalidPortal    = errors.New("portal must link two different cells")
+	ErrPortalExists     = errors.New("cell is already a portal entrance")
+	ErrPortalNotFound   = errors.New("portal not found")
+	ErrNoInfluence      = errors.New("influence field needs at least one source")
+
+	ErrInvalidMovementLayer = errors.New("unknown movement layer")
+	ErrInvalidCellType      = errors.New("unknown cell type")
+)
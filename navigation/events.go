@@ -0,0 +1,20 @@
+package navigation
+
+import "flow/events"
+
+// GoalChanged is published when the navigator's goal moves
+type GoalChanged struct {
+	Previous Position
+	Goal     Position
+}
+
+// FlowFieldRecomputed is published by Poll after recomputed flow fields go live
+type FlowFieldRecomputed struct {
+	Goal    Position
+	Version uint64
+}
+
+// SetEventBus sets the bus the navigator publishes its events on
+func (f *FlowFieldNavigator) SetEventBus(bus *events.Bus) {
+	f.bus = bus
+}
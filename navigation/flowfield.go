@@ -3,203 +3,676 @@ package navigation
 import (
 	"errors"
 	"math"
+	"sync"
+	"sync/atomic"
+
+	"flow/events"
 )
 
-// FlowFieldNavigator implements pathfinding using flow fields
+// FlowFieldNavigator implements pathfinding using flow fields.
+//
+// Cost and goal changes are applied immediately, while the flow fields are
+// recomputed on a background worker and swapped in atomically. Flow lookups
+// read the latest published fields and never block; Version reports which
+// fields are live and Wait blocks until pending changes are published.
 type FlowFieldNavigator struct {
-	config    Config
+	config   Config
+	topology Topology
+
+	// Guarded by mu: the inputs the worker computes fields from
+	mu        sync.Mutex
 	grid      *Grid
 	goal      Position
 	isGoalSet bool
+	profiles  map[string]FieldProfile
+	requested uint64     // Version of the latest change waiting to be published
+	completed uint64     // Version of the latest published fields
+	done      *sync.Cond // Signalled on mu whenever completed advances
+
+	cells   atomic.Pointer[cellSet]  // Immutable copy of costs and cell types, replaced on change
+	field   atomic.Pointer[fieldSet] // Latest published fields
+	pending chan struct{}            // Wakes the worker; holds at most one request
+	quit    chan struct{}
+	closed  sync.Once
+
+	bus    *events.Bus
+	polled uint64 // Version last announced by Poll
+}
+
+// cellSet is an immutable copy of the grid's effective costs, cell types,
+// edge overrides and portals, with the connected components they form
+type cellSet struct {
+	costs     []int // Flat row-major, like the grid
+	cellTypes []CellType
+	edges     map[Edge]int
+	portals   map[Position]Portal
+
+	components    []int // Component of each cell, NoComponent where blocked
+	nextComponent int   // Lowest label not yet handed out
+	directed      bool  // Whether some link only works one way
+}
+
+// flowData holds one integrated field, flat row-major like the grid
+type flowData struct {
+	distances   []float64
+	flowField   []Direction
+	flowVectors []Vector
+	maxDistance float64 // Largest finite distance, the start of the longest path
+}
+
+// fieldSet is an immutable set of flow fields published to readers
+type fieldSet struct {
+	version   uint64
+	goal      Position
+	isGoalSet bool
+	flowData
+	profiles map[string]*profileField
 }
 
-// NewFlowFieldNavigator creates a new flow field navigator with the given configuration
+// NewFlowFieldNavigator creates a new flow field navigator with the given
+// configuration and starts its background worker
 func NewFlowFieldNavigator(config Config) (*FlowFieldNavigator, error) {
 	if err := config.Validate(); err != nil {
 		return nil, err
 	}
 
-	grid := NewGrid(config.GridWidth, config.GridHeight)
+	grid := NewLayeredGrid(config.GridWidth, config.GridHeight, config.levels())
+	grid.WrapX, grid.WrapY = config.WrapX, config.WrapY
+	for _, spec := range config.Layers {
+		layer, err := grid.AddLayer(spec.Name, spec.Mode)
+		if err != nil {
+			return nil, err
+		}
+		layer.Enabled = spec.Enabled
+	}
 
-	return &FlowFieldNavigator{
+	navigator := &FlowFieldNavigator{
 		config:    config,
+		topology:  config.topology(),
 		grid:      grid,
 		isGoalSet: false,
-	}, nil
+		profiles:  make(map[string]FieldProfile),
+		pending:   make(chan struct{}, 1),
+		quit:      make(chan struct{}),
+	}
+	navigator.done = sync.NewCond(&navigator.mu)
+	navigator.field.Store(&fieldSet{})
+	navigator.publishCellsLocked()
+
+	// Air units share a built-in field that flies over obstacles
+	if err := navigator.AddProfile(AirProfile, FieldProfile{Movement: Air}); err != nil {
+		return nil, err
+	}
+
+	go navigator.run()
+	return navigator, nil
+}
+
+// Topology returns how the navigator's cells connect and are laid out
+func (f *FlowFieldNavigator) Topology() Topology {
+	return f.topology
 }
 
-// SetGoal sets the target position and recomputes the flow field
+// Close stops the background worker. Changes made afterwards are never
+// published, so Wait must not be called after Close.
+func (f *FlowFieldNavigator) Close() {
+	f.closed.Do(func() { close(f.quit) })
+}
+
+// SetGoal sets the target position and schedules the flow fields to be recomputed
 func (f *FlowFieldNavigator) SetGoal(goal Position) error {
+	goal = f.wrap(goal)
+
+	f.mu.Lock()
 	if !f.grid.IsValidPosition(goal) {
+		f.mu.Unlock()
 		return ErrInvalidPosition
 	}
 
 	if !f.grid.IsPassable(goal) {
+		f.mu.Unlock()
 		return ErrInvalidGoal
 	}
 
+	previous := f.goal
 	f.goal = goal
 	f.isGoalSet = true
+	f.grid.setGoal(f.grid.Index(goal))
+	f.publishCellsLocked()
+	f.requestLocked()
+	f.mu.Unlock()
 
-	return f.computeFlowField()
+	events.Publish(f.bus, GoalChanged{Previous: previous, Goal: goal})
+	return nil
 }
 
-// GetFlowDirection returns the optimal direction to move from the given position
+// GetFlowDirection returns the optimal direction to move from the given
+// position, or UsePortal if the unit should take the portal it stands on
 func (f *FlowFieldNavigator) GetFlowDirection(pos Position) (Direction, error) {
-	if !f.isGoalSet {
+	field := f.field.Load()
+	if !field.isGoalSet {
 		return Direction{}, ErrInvalidGoal
 	}
 
-	if !f.grid.IsValidPosition(pos) {
+	return f.lookupFlow(field, field.flowField, pos)
+}
+
+// lookupFlow reads the direction for a position from a published flow field
+func (f *FlowFieldNavigator) lookupFlow(field *fieldSet, flowField []Direction, pos Position) (Direction, error) {
+	pos = f.wrap(pos)
+	if !f.inBounds(pos) {
 		return Direction{}, ErrInvalidPosition
 	}
 
 	// If we're at the goal, no movement needed
-	if pos.X == f.goal.X && pos.Y == f.goal.Y {
+	if pos == field.goal {
 		return Direction{X: 0, Y: 0}, nil
 	}
 
-	direction := f.grid.FlowField[pos.Y][pos.X]
+	direction := flowField[f.index(pos)]
 
 	// Check if position is reachable
-	if direction.X == 0 && direction.Y == 0 && (pos.X != f.goal.X || pos.Y != f.goal.Y) {
+	if direction.X == 0 && direction.Y == 0 {
 		return Direction{}, ErrNoPath
 	}
 
 	return direction, nil
 }
 
-// UpdateCosts updates the grid costs and recomputes the flow field if goal is set
+// GetFlowVector returns the smooth unit direction to move from the given position,
+// following the gradient of the distance field. Portal entrances to be taken
+// return a zero vector.
+func (f *FlowFieldNavigator) GetFlowVector(pos Position) (Vector, error) {
+	field := f.field.Load()
+	if !field.isGoalSet {
+		return Vector{}, ErrInvalidGoal
+	}
+
+	return f.lookupVector(field, field.flowData, pos)
+}
+
+// lookupVector reads the flow vector for a position from a published field
+func (f *FlowFieldNavigator) lookupVector(field *fieldSet, data flowData, pos Position) (Vector, error) {
+	pos = f.wrap(pos)
+	if !f.inBounds(pos) {
+		return Vector{}, ErrInvalidPosition
+	}
+
+	// If we're at the goal, no movement needed
+	if pos == field.goal {
+		return Vector{}, nil
+	}
+
+	cell := f.index(pos)
+	vector := data.flowVectors[cell]
+
+	// Units leave portal entrances through the portal, not by walking
+	if data.flowField[cell] == UsePortal {
+		return Vector{}, nil
+	}
+
+	// Check if position is reachable
+	if vector.X == 0 && vector.Y == 0 {
+		return Vector{}, ErrNoPath
+	}
+
+	return vector, nil
+}
+
+// Version returns the version of the published flow fields. It increases each
+// time recomputed fields are swapped in.
+func (f *FlowFieldNavigator) Version() uint64 {
+	return f.field.Load().version
+}
+
+// Wait blocks until every change made so far is reflected in the published fields
+func (f *FlowFieldNavigator) Wait() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for f.completed < f.requested {
+		f.done.Wait()
+	}
+}
+
+// Poll publishes FlowFieldRecomputed if new fields went live since the last
+// poll. Call it from the goroutine that owns the event bus, e.g. once per frame.
+func (f *FlowFieldNavigator) Poll() {
+	field := f.field.Load()
+	if field.version == f.polled {
+		return
+	}
+
+	f.polled = field.version
+	events.Publish(f.bus, FlowFieldRecomputed{Goal: field.goal, Version: field.version})
+}
+
+// UpdateCosts replaces the terrain costs and schedules a recompute if goal is
+// set. On multi-level grids the rows of each level follow the level below.
 func (f *FlowFieldNavigator) UpdateCosts(costs [][]int) error {
-	if len(costs) != f.grid.Height {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(costs) != len(f.grid.Terrain) {
 		return errors.New("cost grid height doesn't match navigator grid")
 	}
 
-	for y := range f.grid.Height {
+	for y := range f.grid.Terrain {
 		if len(costs[y]) != f.grid.Width {
 			return errors.New("cost grid width doesn't match navigator grid")
 		}
-		copy(f.grid.Costs[y], costs[y])
 	}
 
-	// Recompute flow field if goal is set
-	if f.isGoalSet {
-		// Check if goal is still valid
-		if !f.grid.IsPassable(f.goal) {
-			f.isGoalSet = false
-			return ErrInvalidGoal
+	for y := range f.grid.Terrain {
+		copy(f.grid.Terrain[y], costs[y])
+	}
+	f.grid.RecomputeCosts()
+	f.publishCellsLocked()
+
+	return f.recomputeLocked()
+}
+
+// SetCellType changes a cell's type and the costs behind it, then schedules a
+// recompute. Setting Goal moves the goal like SetGoal.
+func (f *FlowFieldNavigator) SetCellType(pos Position, cellType CellType) error {
+	if cellType == Goal {
+		return f.SetGoal(pos)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.grid.SetCellType(pos, cellType); err != nil {
+		return err
+	}
+	f.publishCellsLocked()
+	return f.recomputeLocked()
+}
+
+// AddCostLayer adds a new overlay layer on top of the existing ones
+func (f *FlowFieldNavigator) AddCostLayer(name string, mode CombineMode) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, err := f.grid.AddLayer(name, mode)
+	return err
+}
+
+// RemoveCostLayer deletes an overlay layer and schedules a recompute
+func (f *FlowFieldNavigator) RemoveCostLayer(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.grid.RemoveLayer(name); err != nil {
+		return err
+	}
+	f.publishCellsLocked()
+	return f.recomputeLocked()
+}
+
+// SetLayerEnabled toggles an overlay layer and schedules a recompute
+func (f *FlowFieldNavigator) SetLayerEnabled(name string, enabled bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.grid.SetLayerEnabled(name, enabled); err != nil {
+		return err
+	}
+	f.publishCellsLocked()
+	return f.recomputeLocked()
+}
+
+// UpdateLayer applies a batch of edits to an overlay layer and recomputes once
+func (f *FlowFieldNavigator) UpdateLayer(name string, update func(layer *CostLayer) error) error {
+	return f.UpdateLayers([]string{name}, func(layers []*CostLayer) error {
+		return update(layers[0])
+	})
+}
+
+// UpdateLayers applies a batch of edits to several overlay layers and recomputes once.
+// Layers are passed to update in the order of names. The navigator is locked while
+// update runs, so update must not call back into the navigator.
+func (f *FlowFieldNavigator) UpdateLayers(names []string, update func(layers []*CostLayer) error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	layers := make([]*CostLayer, len(names))
+	for i, name := range names {
+		layer, ok := f.grid.Layer(name)
+		if !ok {
+			return ErrLayerNotFound
 		}
+		layers[i] = layer
+	}
+
+	// Recompute even if the update failed part way so costs match the layers
+	updateErr := update(layers)
+	f.grid.RecomputeCosts()
+	f.publishCellsLocked()
+	if err := f.recomputeLocked(); err != nil {
+		return err
+	}
+
+	return updateErr
+}
 
-		return f.computeFlowField()
+// recomputeLocked schedules a flow field rebuild after a cost change if a goal
+// is set. The caller must hold mu.
+func (f *FlowFieldNavigator) recomputeLocked() error {
+	if !f.isGoalSet {
+		return nil
+	}
+
+	// Check if goal is still valid
+	if !f.grid.IsPassable(f.goal) {
+		f.isGoalSet = false
+		f.grid.setGoal(-1)
+		f.publishCellsLocked()
+		f.requestLocked()
+		return ErrInvalidGoal
 	}
 
+	f.requestLocked()
 	return nil
 }
 
+// publishCellsLocked replaces the shared cell snapshot with a copy of the
+// current effective costs, cell types, edges and portals, labelled with their
+// connected components. The caller must hold mu.
+func (f *FlowFieldNavigator) publishCellsLocked() {
+	cells := &cellSet{
+		costs:     append([]int(nil), f.grid.costs...),
+		cellTypes: append([]CellType(nil), f.grid.cellTypes...),
+		edges:     f.grid.Edges(),
+		portals:   f.grid.Portals(),
+	}
+	f.labelComponents(cells, f.cells.Load())
+	f.cells.Store(cells)
+}
+
+// requestLocked marks the inputs as changed and wakes the worker. The caller
+// must hold mu.
+func (f *FlowFieldNavigator) requestLocked() {
+	f.requested++
+	select {
+	case f.pending <- struct{}{}:
+	default:
+		// A request is already queued and will pick up this change too
+	}
+}
+
 // GetGoal returns the current goal position
 func (f *FlowFieldNavigator) GetGoal() Position {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
 	return f.goal
 }
 
-// GetGrid returns a copy of the current grid state
+// GetGrid returns a deep copy of the current grid state, with the flow field and
+// distances taken from the published fields. Prefer View for read-only access.
 func (f *FlowFieldNavigator) GetGrid() *Grid {
-	// Create a deep copy to prevent external modification
-	gridCopy := NewGrid(f.grid.Width, f.grid.Height)
+	f.mu.Lock()
+	defer f.mu.Unlock()
 
-	for y := range f.grid.Height {
-		copy(gridCopy.Costs[y], f.grid.Costs[y])
-		copy(gridCopy.FlowField[y], f.grid.FlowField[y])
-		copy(gridCopy.Distances[y], f.grid.Distances[y])
+	// Create a deep copy to prevent external modification
+	gridCopy := NewLayeredGrid(f.grid.Width, f.grid.Height, f.grid.Levels)
+	gridCopy.WrapX, gridCopy.WrapY = f.grid.WrapX, f.grid.WrapY
+	field := f.field.Load()
+
+	copy(gridCopy.terrain, f.grid.terrain)
+	copy(gridCopy.costs, f.grid.costs)
+	copy(gridCopy.cellTypes, f.grid.cellTypes)
+	gridCopy.goal = f.grid.goal
+	gridCopy.edges = f.grid.Edges()
+	gridCopy.portals = f.grid.Portals()
+	copy(gridCopy.flowField, field.flowField)
+	copy(gridCopy.distances, field.distances)
+
+	for _, layer := range f.grid.layers {
+		gridCopy.layers = append(gridCopy.layers, layer.clone())
 	}
 
 	return gridCopy
 }
 
-// computeFlowField calculates the flow field using Dijkstra's algorithm
-func (f *FlowFieldNavigator) computeFlowField() error {
-	// Reset distances and flow field
-	for y := range f.grid.Height {
-		for x := range f.grid.Width {
-			f.grid.Distances[y][x] = math.MaxInt32
-			f.grid.FlowField[y][x] = Direction{X: 0, Y: 0}
+// run recomputes and publishes the flow fields whenever a change is requested
+func (f *FlowFieldNavigator) run() {
+	for {
+		select {
+		case <-f.quit:
+			return
+		case <-f.pending:
+			f.computeFlowField()
 		}
 	}
+}
 
-	// Initialize goal
-	f.grid.Distances[f.goal.Y][f.goal.X] = 0
-	queue := []Position{f.goal}
+// computeFlowField snapshots the inputs, integrates the default flow field and
+// every profile field into fresh buffers, and publishes them
+func (f *FlowFieldNavigator) computeFlowField() {
+	f.mu.Lock()
+	field := &fieldSet{
+		version:   f.requested,
+		goal:      f.goal,
+		isGoalSet: f.isGoalSet,
+		profiles:  make(map[string]*profileField, len(f.profiles)),
+	}
+
+	// The cell snapshot is immutable, so integration can read its costs directly
+	cells := f.cells.Load()
+	costs := cells.costs
+	if field.isGoalSet {
+		for name, profile := range f.profiles {
+			profileCosts := make([]int, len(costs))
+			f.grid.composeCosts(profile.LayerWeights, profile.Movement, profileCosts)
+			field.profiles[name] = &profileField{costs: profileCosts}
+		}
+	}
+	f.mu.Unlock()
 
-	// Phase 1: Dijkstra-style distance propagation
-	for len(queue) > 0 {
-		current := queue[0]
-		queue = queue[1:]
+	// Integration runs without the lock so writers never wait on it
+	if field.isGoalSet {
+		field.flowData = f.integrate(field.goal, costs, cells)
+		for _, profile := range field.profiles {
+			profile.flowData = f.integrate(field.goal, profile.costs, cells)
+		}
+	}
 
-		currentDist := f.grid.Distances[current.Y][current.X]
+	f.field.Store(field)
 
-		// Check all configured directions
-		for _, dir := range f.config.Directions {
-			next := Position{
-				X: current.X + dir.X,
-				Y: current.Y + dir.Y,
-			}
+	f.mu.Lock()
+	f.completed = max(f.completed, field.version)
+	f.done.Broadcast()
+	f.mu.Unlock()
+}
+
+// integrate computes distances, flow directions and flow vectors toward goal
+// for the given flat row-major costs, using the cell snapshot's edges and
+// portals and the configured integration method. Fast marching has no notion
+// of individual steps, so grids with edges or portals always use Dijkstra. It
+// only reads immutable navigator state, so it is safe to run without holding mu.
+func (f *FlowFieldNavigator) integrate(goal Position, costs []int, cells *cellSet) flowData {
+	var distances []float64
+	switch {
+	case f.config.Integration == FastMarching && len(cells.edges) == 0 && len(cells.portals) == 0:
+		distances = f.fastMarch(goal, costs)
+	default:
+		distances = f.dijkstra(goal, costs, cells)
+	}
+
+	data := f.descend(f.index(goal), costs, cells, distances)
+	for _, distance := range distances {
+		if !math.IsInf(distance, 1) {
+			data.maxDistance = max(data.maxDistance, distance)
+		}
+	}
+	return data
+}
+
+// descend builds the flow directions and vectors that walk down a distance
+// field. start is the flat index of the cell to leave still, or -1 for none.
+func (f *FlowFieldNavigator) descend(start int, costs []int, cells *cellSet, distances []float64) flowData {
+	flowField := f.flowDirections(start, costs, cells, distances)
+	return flowData{
+		distances:   distances,
+		flowField:   flowField,
+		flowVectors: f.flowVectors(start, cells.edges, distances, flowField),
+	}
+}
 
-			// Skip if out of bounds or blocked
-			if !f.grid.IsValidPosition(next) || !f.grid.IsPassable(next) {
+// dijkstra computes distances toward goal over the topology's neighbours and
+// any portals, propagating backwards along each step so edge overrides apply
+// in the direction units travel. Integer costs are summed as fractional
+// distances so diagonal steps keep their exact multiplier.
+func (f *FlowFieldNavigator) dijkstra(goal Position, costs []int, cells *cellSet) []float64 {
+	distances := make([]float64, len(costs))
+	for i := range distances {
+		distances[i] = math.Inf(1)
+	}
+
+	// Initialize goal
+	start := f.index(goal)
+	distances[start] = 0
+	f.relax(distances, []int{start}, costs, cells)
+
+	return distances
+}
+
+// relax lowers distances until no step or portal into a queued cell, or any
+// cell lowered on the way, offers a shorter path. Distances of queued cells
+// act as seeds and may be any finite value.
+func (f *FlowFieldNavigator) relax(distances []float64, queue []int, costs []int, cells *cellSet) {
+	portals := f.portalsByExit(cells.portals)
+
+	// Distance propagation over flat indices
+	for head := 0; head < len(queue); head++ {
+		current := queue[head]
+		currentPos := f.position(current)
+		currentDist := distances[current]
+
+		// Check every neighbour in the topology, which stays on the same level
+		for _, dir := range f.topology.Neighbors(currentPos) {
+			// Skip if out of bounds
+			nextPos, ok := f.neighbor(currentPos, dir)
+			if !ok {
 				continue
 			}
+			next := f.index(nextPos)
 
-			// Calculate movement cost
-			moveCost := f.grid.Costs[next.Y][next.X]
-
-			// Apply diagonal cost multiplier if needed
-			if f.isDiagonal(dir) {
-				moveCost = int(float64(moveCost) * f.config.DiagonalCost)
+			// Units move from next back into current, so cost that step
+			moveCost := f.stepCost(costs, cells.edges, nextPos, Direction{X: -dir.X, Y: -dir.Y})
+			if moveCost < 0 {
+				continue
 			}
 
 			newDist := currentDist + moveCost
 
 			// Update if we found a shorter path
-			if newDist < f.grid.Distances[next.Y][next.X] {
-				f.grid.Distances[next.Y][next.X] = newDist
+			if newDist < distances[next] {
+				distances[next] = newDist
 				queue = append(queue, next)
 			}
 		}
-	}
-
-	// Phase 2: Compute flow directions
-	for y := range f.grid.Height {
-		for x := range f.grid.Width {
-			pos := Position{X: x, Y: y}
 
-			// Skip obstacles and goal
-			if !f.grid.IsPassable(pos) || (x == f.goal.X && y == f.goal.Y) {
+		// Portals exiting here make their entrances reachable too
+		for _, portal := range portals[current] {
+			next := f.index(portal.From)
+			if costs[next] == -1 {
 				continue
 			}
 
-			bestDist := f.grid.Distances[y][x]
-			bestDir := Direction{X: 0, Y: 0}
+			newDist := currentDist + float64(portal.Cost)
+			if newDist < distances[next] {
+				distances[next] = newDist
+				queue = append(queue, next)
+			}
+		}
+	}
+}
+
+// flowDirections points each reachable cell except start at its neighbour
+// closest to the goal that it is allowed to step into, or at UsePortal where
+// the cell's portal beats every step, walking cells in storage order
+func (f *FlowFieldNavigator) flowDirections(start int, costs []int, cells *cellSet, distances []float64) []Direction {
+	flowField := make([]Direction, len(costs))
+
+	for cell := range costs {
+		// Skip obstacles and goal
+		if costs[cell] == -1 || cell == start {
+			continue
+		}
+
+		bestDist := distances[cell]
+		bestDir := Direction{X: 0, Y: 0}
+		walk := math.Inf(1) // Cheapest route to the goal that starts with a step
 
-			// Find neighbor with minimum distance
-			for _, dir := range f.config.Directions {
-				neighbor := Position{X: x + dir.X, Y: y + dir.Y}
+		// Find neighbor with minimum distance
+		pos := f.position(cell)
+		for _, dir := range f.topology.Neighbors(pos) {
+			neighbor, ok := f.neighbor(pos, dir)
+			if !ok {
+				continue
+			}
+			if isBlocked(cells.edges, pos, dir) || f.cutsCorner(costs, pos, dir) {
+				continue
+			}
 
-				if f.grid.IsValidPosition(neighbor) {
-					neighborDist := f.grid.Distances[neighbor.Y][neighbor.X]
-					if neighborDist < bestDist {
-						bestDist = neighborDist
-						bestDir = dir
-					}
-				}
+			neighborDist := distances[f.index(neighbor)]
+			if neighborDist < bestDist {
+				bestDist = neighborDist
+				bestDir = dir
+			}
+			if moveCost := f.stepCost(costs, cells.edges, pos, dir); moveCost >= 0 {
+				walk = min(walk, neighborDist+moveCost)
 			}
+		}
 
-			f.grid.FlowField[y][x] = bestDir
+		// Take the portal only when it is strictly cheaper than walking, so the
+		// ends of zero-cost portal pairs never send units back and forth. Cells
+		// that can't reach the goal at all have no path to start.
+		if portal, ok := cells.portals[pos]; ok && !math.IsInf(distances[cell], 1) {
+			exit := distances[f.index(portal.To)]
+			if exit+float64(portal.Cost) < walk {
+				bestDir = UsePortal
+			}
 		}
+
+		flowField[cell] = bestDir
 	}
 
-	return nil
+	return flowField
+}
+
+// Wrapping reports whether the grid wraps around horizontally and vertically
+func (f *FlowFieldNavigator) Wrapping() (wrapX, wrapY bool) {
+	return f.config.WrapX, f.config.WrapY
+}
+
+// wrap wraps a position onto the grid on the configured wrapping axes
+func (f *FlowFieldNavigator) wrap(pos Position) Position {
+	return wrapPosition(pos, f.config.GridWidth, f.config.GridHeight, f.config.WrapX, f.config.WrapY)
+}
+
+// neighbor returns the cell one step away in dir, wrapped onto the grid, and
+// whether that cell exists
+func (f *FlowFieldNavigator) neighbor(pos Position, dir Direction) (Position, bool) {
+	next := f.wrap(pos.step(dir))
+	return next, f.inBounds(next)
+}
+
+// inBounds checks a position against the configured grid size without touching the grid
+func (f *FlowFieldNavigator) inBounds(pos Position) bool {
+	return pos.X >= 0 && pos.X < f.config.GridWidth && pos.Y >= 0 && pos.Y < f.config.GridHeight &&
+		pos.Level >= 0 && pos.Level < f.config.levels()
+}
+
+// index returns the flat index of a position in the navigator's grid layout
+func (f *FlowFieldNavigator) index(pos Position) int {
+	return cellIndex(pos, f.config.GridWidth, f.config.GridHeight)
 }
 
-// isDiagonal checks if a direction is diagonal
-func (f *FlowFieldNavigator) isDiagonal(dir Direction) bool {
-	return dir.X != 0 && dir.Y != 0
+// position returns the position of a flat index in the navigator's grid layout
+func (f *FlowFieldNavigator) position(index int) Position {
+	return cellPosition(index, f.config.GridWidth, f.config.GridHeight)
 }
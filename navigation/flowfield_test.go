@@ -0,0 +1,206 @@
+package navigation
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+)
+
+// newTestNavigator returns a navigator that is closed when the test ends
+func newTestNavigator(t testing.TB, config Config) *FlowFieldNavigator {
+	t.Helper()
+	navigator, err := NewFlowFieldNavigator(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(navigator.Close)
+	return navigator
+}
+
+func TestConcurrentWritesAndReads(t *testing.T) {
+	const size = 24
+	navigator := newTestNavigator(t, EightWayConfig(size, size))
+	if err := navigator.SetGoal(Position{X: 0, Y: 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	var writers, readers sync.WaitGroup
+	done := make(chan struct{})
+
+	writers.Add(3)
+	go func() {
+		defer writers.Done()
+		for i := range 200 {
+			_ = navigator.SetGoal(Position{X: i % size, Y: 0})
+		}
+	}()
+	go func() {
+		defer writers.Done()
+		for i := range 200 {
+			cellType := Obstacle
+			if i%2 == 0 {
+				cellType = Passable
+			}
+			_ = navigator.SetCellType(Position{X: i % size, Y: size / 2}, cellType)
+		}
+	}()
+	go func() {
+		defer writers.Done()
+		for i := range 200 {
+			err := navigator.UpdateLayers([]string{LayerBuildings, LayerSlow}, func(layers []*CostLayer) error {
+				layers[0].Clear()
+				if err := layers[0].Set(Position{X: i % size, Y: size - 2}, -1); err != nil {
+					return err
+				}
+				return layers[1].Set(Position{X: i % size, Y: size - 3}, 3)
+			})
+			if err != nil && !errors.Is(err, ErrInvalidGoal) {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	for range 4 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				pos := Position{X: size - 1, Y: size - 1}
+				_, _ = navigator.GetFlowDirection(pos)
+				_, _ = navigator.GetFlowVector(pos)
+				view := navigator.View()
+				_ = view.FlowAt(pos)
+				_ = view.DistanceAt(pos)
+				_ = view.ComponentAt(pos)
+				_ = navigator.IsReachable(pos, Position{})
+				_ = navigator.GetGrid()
+				_ = navigator.Version()
+			}
+		}()
+	}
+
+	writers.Wait()
+	close(done)
+	readers.Wait()
+	navigator.Wait()
+}
+
+func TestWaitPublishesLatestChange(t *testing.T) {
+	navigator := newTestNavigator(t, EightWayConfig(10, 10))
+
+	// Nothing has been requested yet, so Wait must not block
+	navigator.Wait()
+
+	goal := Position{X: 9, Y: 9}
+	if err := navigator.SetGoal(goal); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+
+	view := navigator.View()
+	if published, ok := view.Goal(); !ok || published != goal {
+		t.Fatalf("published goal = %v, %v; want %v", published, ok, goal)
+	}
+	if distance := view.DistanceAt(goal); distance != 0 {
+		t.Fatalf("distance at goal = %v, want 0", distance)
+	}
+	first := navigator.Version()
+	if first == 0 {
+		t.Fatal("version did not advance after SetGoal")
+	}
+
+	// Walling off a cell is visible once Wait returns, under a newer version
+	wall := Position{X: 0, Y: 0}
+	if err := navigator.SetCellType(wall, Obstacle); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+
+	if second := navigator.Version(); second <= first {
+		t.Fatalf("version = %d after a change, want more than %d", second, first)
+	}
+	if distance := navigator.DistanceAt(wall); !math.IsInf(distance, 1) {
+		t.Fatalf("distance at obstacle = %v, want +Inf", distance)
+	}
+	if _, err := navigator.GetFlowDirection(wall); !errors.Is(err, ErrNoPath) {
+		t.Fatalf("flow at obstacle: err = %v, want ErrNoPath", err)
+	}
+}
+
+func TestVersionIsMonotonic(t *testing.T) {
+	navigator := newTestNavigator(t, EightWayConfig(16, 16))
+	if err := navigator.SetGoal(Position{}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 100 {
+			_ = navigator.SetGoal(Position{X: i % 16, Y: 15})
+		}
+		navigator.Wait()
+	}()
+
+	last := uint64(0)
+	for {
+		version := navigator.Version()
+		if version < last {
+			t.Fatalf("version went back from %d to %d", last, version)
+		}
+		last = version
+
+		select {
+		case <-done:
+			if goal, _ := navigator.View().Goal(); goal != (Position{X: 99 % 16, Y: 15}) {
+				t.Fatalf("published goal = %v after Wait, want the last one set", goal)
+			}
+			return
+		default:
+		}
+	}
+}
+
+func TestStraightCorridorFlowsStraight(t *testing.T) {
+	navigator := newTestNavigator(t, EightWayConfig(20, 3))
+	if err := navigator.SetGoal(Position{X: 0, Y: 1}); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+
+	// The rows beside the goal's take exactly one sqrt(2) diagonal; a cost
+	// rounded down toward 1 would make them as close as the middle row and let
+	// diagonal zig-zags tie with straight steps
+	for x := 1; x < 20; x++ {
+		for _, y := range []int{0, 2} {
+			pos := Position{X: x, Y: y}
+			want := float64(x-1) + math.Sqrt2
+			if distance := navigator.DistanceAt(pos); math.Abs(distance-want) > 1e-9 {
+				t.Errorf("distance at %v = %v, want %v", pos, distance, want)
+			}
+		}
+	}
+
+	left := Direction{X: -1, Y: 0}
+	for x := 1; x < 20; x++ {
+		pos := Position{X: x, Y: 1}
+		if distance := navigator.DistanceAt(pos); distance != float64(x) {
+			t.Errorf("distance at %v = %v, want %d", pos, distance, x)
+		}
+		direction, err := navigator.GetFlowDirection(pos)
+		if err != nil {
+			t.Fatalf("flow at %v: %v", pos, err)
+		}
+		if direction != left {
+			t.Errorf("flow at %v = %v, want %v", pos, direction, left)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package navigation
+
+import (
+	"math"
+	"sort"
+)
+
+// FleeWeight is the usual weight of a source to flee from. It is a little
+// steeper than 1 so that, once re-relaxed, running far away beats hiding in a
+// nearby dead end.
+const FleeWeight = -1.2
+
+// Influence is a source that pulls units toward it (positive Weight) or
+// pushes them away (negative Weight), scaled by the path distance from it
+type Influence struct {
+	Source Position
+	Weight float64
+}
+
+// InfluenceField is a steering field built from attracting and repelling
+// sources. It is computed once from the ground costs at the time it was built
+// and never changes, so it is safe to share between goroutines.
+type InfluenceField struct {
+	navigator *FlowFieldNavigator
+	flowData  // distances holds the potential units walk down
+}
+
+// BuildFleeField returns a field leading away from a danger source, e.g. a
+// player hero or a bomb
+func (f *FlowFieldNavigator) BuildFleeField(source Position) (*InfluenceField, error) {
+	return f.BuildInfluenceField(Influence{Source: source, Weight: FleeWeight})
+}
+
+// BuildInfluenceField combines attracting and repelling sources into one
+// steering field. Each cell's potential is the weighted sum of its distances
+// to the sources; the potentials are then re-relaxed over the grid so units
+// walking downhill take open escape routes instead of shallow dead ends. The
+// field is computed on the calling goroutine with Dijkstra distances.
+func (f *FlowFieldNavigator) BuildInfluenceField(sources ...Influence) (*InfluenceField, error) {
+	if len(sources) == 0 {
+		return nil, ErrNoInfluence
+	}
+	for _, source := range sources {
+		if !f.inBounds(f.wrap(source.Source)) {
+			return nil, ErrInvalidPosition
+		}
+	}
+
+	cells := f.cells.Load()
+	costs := cells.costs
+
+	// Weighted sum of distances; a source contributes nothing where it can't be reached
+	potential := make([]float64, len(costs))
+	for _, source := range sources {
+		distances := f.dijkstra(f.wrap(source.Source), costs, cells)
+		for i, distance := range distances {
+			if !math.IsInf(distance, 1) {
+				potential[i] += source.Weight * distance
+			}
+		}
+	}
+
+	// Re-relax from every open cell, lowest first, so each potential is at
+	// most one step above a neighbour's
+	var queue []int
+	for i, cost := range costs {
+		if cost == -1 {
+			potential[i] = math.Inf(1)
+			continue
+		}
+		queue = append(queue, i)
+	}
+	sort.SliceStable(queue, func(a, b int) bool { return potential[queue[a]] < potential[queue[b]] })
+	f.relax(potential, queue, costs, cells)
+
+	return &InfluenceField{
+		navigator: f,
+		flowData:  f.descend(-1, costs, cells, potential),
+	}, nil
+}
+
+// Direction returns the direction to move from a position, UsePortal if the
+// unit should take the portal it stands on, or a zero direction where the
+// field comes to rest
+func (i *InfluenceField) Direction(pos Position) (Direction, error) {
+	cell, err := i.cell(pos)
+	if err != nil {
+		return Direction{}, err
+	}
+	return i.flowField[cell], nil
+}
+
+// Vector returns the smooth unit direction to move from a position, or a zero
+// vector where the field comes to rest or at a portal entrance to be taken
+func (i *InfluenceField) Vector(pos Position) (Vector, error) {
+	cell, err := i.cell(pos)
+	if err != nil {
+		return Vector{}, err
+	}
+	return i.flowVectors[cell], nil
+}
+
+// Potential returns a position's potential, lower being more desirable, or
+// +Inf outside the grid and on blocked cells
+func (i *InfluenceField) Potential(pos Position) float64 {
+	cell, err := i.cell(pos)
+	if err != nil {
+		return math.Inf(1)
+	}
+	return i.distances[cell]
+}
+
+// cell returns the flat index of a position, failing outside the grid and on
+// cells the field can't move units from
+func (i *InfluenceField) cell(pos Position) (int, error) {
+	pos = i.navigator.wrap(pos)
+	if !i.navigator.inBounds(pos) {
+		return 0, ErrInvalidPosition
+	}
+
+	cell := i.navigator.index(pos)
+	if math.IsInf(i.distances[cell], 1) {
+		return 0, ErrNoPath
+	}
+	return cell, nil
+}
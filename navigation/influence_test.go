@@ -0,0 +1,106 @@
+package navigation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFleePrefersOpenEscapeOverDeadEnd(t *testing.T) {
+	// The source sits at the left end of a short corridor that runs on into a
+	// dead end. The only way out turns down at x = 2, back toward the source,
+	// into a long corridor along the bottom (cut short here; the grid is 30
+	// cells wide):
+	//
+	//	S.......#########
+	//	##.##############
+	//	##.##############
+	//	##...............
+	const width = 30
+	navigator := newTestNavigator(t, Config{
+		GridWidth:    width,
+		GridHeight:   4,
+		Directions:   FourWayDirections,
+		DiagonalCost: 1,
+	})
+	for y := range 4 {
+		for x := range width {
+			open := (y == 0 && x < 8) || x == 2 || (y == 3 && x >= 2)
+			if !open {
+				if err := navigator.SetCellType(Position{X: x, Y: y}, Obstacle); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	field, err := navigator.BuildFleeField(Position{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stepping right moves further from the source for now, but only the
+	// long corridor gets away from it
+	junction := Position{X: 4}
+	if direction, err := field.Direction(junction); err != nil || direction != (Direction{X: -1}) {
+		t.Errorf("flee direction at %v = %v, %v; want left toward the escape", junction, direction, err)
+	}
+	down := Position{X: 2}
+	if direction, err := field.Direction(down); err != nil || direction != (Direction{Y: 1}) {
+		t.Errorf("flee direction at %v = %v, %v; want down", down, direction, err)
+	}
+	if field.Potential(Position{X: width - 1, Y: 3}) >= field.Potential(Position{X: 7}) {
+		t.Error("the end of the dead end is at least as desirable as the end of the escape")
+	}
+}
+
+func TestInfluenceCombinesAttractAndRepel(t *testing.T) {
+	attractor, repeller := Position{X: 5}, Position{}
+	tests := []struct {
+		name    string
+		weights [2]float64 // Attractor, repeller
+		want    map[int]Direction
+	}{
+		{
+			// The pull outweighs the push, so units settle on the attractor
+			name:    "attraction wins",
+			weights: [2]float64{2, -1},
+			want:    map[int]Direction{2: {X: 1}, 8: {X: -1}, 5: {}},
+		},
+		{
+			// The push outweighs the pull, so units are driven past it
+			name:    "repulsion wins",
+			weights: [2]float64{1, -3},
+			want:    map[int]Direction{2: {X: 1}, 5: {X: 1}, 8: {X: 1}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			navigator := newCorridor(t, 11)
+			field, err := navigator.BuildInfluenceField(
+				Influence{Source: attractor, Weight: test.weights[0]},
+				Influence{Source: repeller, Weight: test.weights[1]},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for x, want := range test.want {
+				pos := Position{X: x}
+				if direction, err := field.Direction(pos); err != nil || direction != want {
+					t.Errorf("direction at %v = %v, %v; want %v", pos, direction, err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildInfluenceFieldErrors(t *testing.T) {
+	navigator := newCorridor(t, 3)
+	if _, err := navigator.BuildInfluenceField(); !errors.Is(err, ErrNoInfluence) {
+		t.Errorf("no sources: err = %v, want ErrNoInfluence", err)
+	}
+	if _, err := navigator.BuildFleeField(Position{X: 5}); !errors.Is(err, ErrInvalidPosition) {
+		t.Errorf("source outside the grid: err = %v, want ErrInvalidPosition", err)
+	}
+}
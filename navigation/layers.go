@@ -0,0 +1,254 @@
+package navigation
+
+import "math"
+
+// CombineMode determines how an overlay layer merges into the effective costs
+type CombineMode int
+
+const (
+	// CombineAdd adds the layer cost on top of the costs below it
+	CombineAdd CombineMode = iota
+	// CombineMax keeps the larger of the layer cost and the costs below it
+	CombineMax
+)
+
+// Well-known overlay layer names
+const (
+	LayerBuildings    = "buildings"
+	LayerDangerGround = "danger-ground" // Turret coverage that can hit ground units
+	LayerDangerAir    = "danger-air"    // Turret coverage that can hit air units
+	LayerSlow         = "slow"
+)
+
+// DangerLayer returns the danger layer covering units on a movement layer
+func DangerLayer(movement MovementLayer) string {
+	if movement == Air {
+		return LayerDangerAir
+	}
+	return LayerDangerGround
+}
+
+// LayerSpec describes an overlay layer to create with the navigator
+type LayerSpec struct {
+	Name    string
+	Mode    CombineMode
+	Enabled bool
+}
+
+// CostLayer is a named overlay of per-cell costs composed over the terrain
+type CostLayer struct {
+	Name    string
+	Mode    CombineMode
+	Enabled bool
+	Costs   [][]int // Row views of the costs: -1 blocks the cell, 0 leaves it unchanged
+
+	width, height, levels int
+	costs                 []int // Flat storage behind Costs, laid out like the grid
+}
+
+// newCostLayer creates an empty overlay layer with the given dimensions
+func newCostLayer(name string, mode CombineMode, width, height, levels int) *CostLayer {
+	costs := make([]int, width*height*levels)
+	return &CostLayer{
+		Name:    name,
+		Mode:    mode,
+		Enabled: true,
+		Costs:   rowViews(costs, width, height*levels),
+		width:   width,
+		height:  height,
+		levels:  levels,
+		costs:   costs,
+	}
+}
+
+// contains checks if a position is within the layer's bounds
+func (l *CostLayer) contains(pos Position) bool {
+	return pos.X >= 0 && pos.X < l.width && pos.Y >= 0 && pos.Y < l.height && pos.Level >= 0 && pos.Level < l.levels
+}
+
+// Set sets the overlay cost for a position
+func (l *CostLayer) Set(pos Position, cost int) error {
+	if !l.contains(pos) {
+		return ErrInvalidPosition
+	}
+	if cost < -1 {
+		return ErrInvalidCost
+	}
+	l.costs[cellIndex(pos, l.width, l.height)] = cost
+	return nil
+}
+
+// At returns the overlay cost for a position, or 0 if it is out of bounds
+func (l *CostLayer) At(pos Position) int {
+	if !l.contains(pos) {
+		return 0
+	}
+	return l.costs[cellIndex(pos, l.width, l.height)]
+}
+
+// Data returns the flat row-major costs, shared with the layer
+func (l *CostLayer) Data() []int {
+	return l.costs
+}
+
+// Clear resets every cell of the layer to zero
+func (l *CostLayer) Clear() {
+	clear(l.costs)
+}
+
+// clone returns a deep copy of the layer
+func (l *CostLayer) clone() *CostLayer {
+	layer := newCostLayer(l.Name, l.Mode, l.width, l.height, l.levels)
+	layer.Enabled = l.Enabled
+	copy(layer.costs, l.costs)
+	return layer
+}
+
+// AddLayer appends a new overlay layer on top of the existing ones
+func (g *Grid) AddLayer(name string, mode CombineMode) (*CostLayer, error) {
+	if _, ok := g.Layer(name); ok {
+		return nil, ErrLayerExists
+	}
+	layer := newCostLayer(name, mode, g.Width, g.Height, g.Levels)
+	g.layers = append(g.layers, layer)
+	return layer, nil
+}
+
+// Layer returns the overlay layer with the given name
+func (g *Grid) Layer(name string) (*CostLayer, bool) {
+	for _, layer := range g.layers {
+		if layer.Name == name {
+			return layer, true
+		}
+	}
+	return nil, false
+}
+
+// Layers returns the overlay layers in composition order
+func (g *Grid) Layers() []*CostLayer {
+	return g.layers
+}
+
+// RemoveLayer deletes an overlay layer and recomputes the effective costs
+func (g *Grid) RemoveLayer(name string) error {
+	for i, layer := range g.layers {
+		if layer.Name == name {
+			g.layers = append(g.layers[:i], g.layers[i+1:]...)
+			g.RecomputeCosts()
+			return nil
+		}
+	}
+	return ErrLayerNotFound
+}
+
+// SetLayerEnabled toggles an overlay layer and recomputes the effective costs
+func (g *Grid) SetLayerEnabled(name string, enabled bool) error {
+	layer, ok := g.Layer(name)
+	if !ok {
+		return ErrLayerNotFound
+	}
+	layer.Enabled = enabled
+	g.RecomputeCosts()
+	return nil
+}
+
+// SetLayerCost sets the overlay cost for a position and updates its effective cost
+func (g *Grid) SetLayerCost(name string, pos Position, cost int) error {
+	layer, ok := g.Layer(name)
+	if !ok {
+		return ErrLayerNotFound
+	}
+	if err := layer.Set(pos, cost); err != nil {
+		return err
+	}
+	g.recomputeCell(g.Index(pos))
+	return nil
+}
+
+// RecomputeCosts rebuilds the effective costs from the terrain and enabled layers
+func (g *Grid) RecomputeCosts() {
+	for i := range g.costs {
+		g.recomputeCell(i)
+	}
+}
+
+// composeCosts fills flat row-major dst with costs composed using per-layer
+// weights for a movement layer
+func (g *Grid) composeCosts(weights map[string]float64, movement MovementLayer, dst []int) {
+	for i := range dst {
+		dst[i] = g.composeCell(i, weights, movement)
+	}
+}
+
+// recomputeCell updates the effective cost and type of the cell at a flat index
+func (g *Grid) recomputeCell(i int) {
+	g.costs[i] = g.composeCell(i, nil, Ground)
+	g.cellTypes[i] = g.classifyCell(i)
+}
+
+// classifyCell derives a cell's type from the goal marker, terrain and enabled
+// blocking layers, so cell types always agree with the costs
+func (g *Grid) classifyCell(i int) CellType {
+	if i == g.goal {
+		return Goal
+	}
+	if g.terrain[i] == -1 {
+		return Obstacle
+	}
+
+	for _, layer := range g.layers {
+		if !layer.Enabled || layer.costs[i] != -1 {
+			continue
+		}
+		if layer.Name == LayerBuildings {
+			return Building
+		}
+		return Obstacle
+	}
+
+	return Passable
+}
+
+// composeCell combines the terrain and overlay costs of the cell at a flat index.
+// Layers listed in weights are scaled by their weight, others contribute only
+// when enabled. Air movement treats blocked cells as plain passable terrain.
+func (g *Grid) composeCell(i int, weights map[string]float64, movement MovementLayer) int {
+	cost := g.terrain[i]
+	if cost == -1 {
+		if movement != Air {
+			return -1
+		}
+		cost = 1
+	}
+
+	for _, layer := range g.layers {
+		weight, ok := weights[layer.Name]
+		if !ok {
+			if !layer.Enabled {
+				continue
+			}
+			weight = 1
+		}
+		if weight == 0 {
+			continue
+		}
+
+		value := layer.costs[i]
+		if value == -1 {
+			if movement == Air {
+				continue
+			}
+			return -1
+		}
+		value = int(math.Round(float64(value) * weight))
+
+		switch layer.Mode {
+		case CombineAdd:
+			cost += value
+		case CombineMax:
+			cost = max(cost, value)
+		}
+	}
+
+	return cost
+}
@@ -0,0 +1,122 @@
+package navigation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// MapFile is the JSON description of a navigation map's terrain, edges and portals
+type MapFile struct {
+	Width   int        `json:"width"`
+	Height  int        `json:"height"`
+	Levels  int        `json:"levels,omitempty"`  // Stacked floors; a single level if omitted
+	Terrain [][]int    `json:"terrain,omitempty"` // Rows of terrain costs, each level's after the last, -1 for obstacles; cost 1 everywhere if omitted
+	Edges   []EdgeSpec `json:"edges,omitempty"`
+	Portals []Portal   `json:"portals,omitempty"` // Include one each way for stairs between levels
+}
+
+// EdgeSpec describes one step in a map file
+type EdgeSpec struct {
+	X      int       `json:"x"`               // Cell the step leaves
+	Y      int       `json:"y"`               // Cell the step leaves
+	Level  int       `json:"level,omitempty"` // Level of the cell the step leaves
+	Dir    Direction `json:"dir"`             // Direction of travel, e.g. "right" or "up-left"
+	Cost   *int      `json:"cost,omitempty"`  // Cost override, BlockedEdge to forbid the step; kept if omitted
+	OneWay bool      `json:"oneWay"`          // Forbid the step back
+}
+
+// edge returns the step the spec describes
+func (s EdgeSpec) edge() Edge {
+	return Edge{From: Position{X: s.X, Y: s.Y, Level: s.Level}, Dir: s.Dir}
+}
+
+// LoadMap decodes a map from JSON
+func LoadMap(reader io.Reader) (*MapFile, error) {
+	var m MapFile
+	if err := json.NewDecoder(reader).Decode(&m); err != nil {
+		return nil, fmt.Errorf("decode map: %w", err)
+	}
+	return &m, nil
+}
+
+// LoadMapFile decodes a map from a JSON data file
+func LoadMapFile(path string) (*MapFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return LoadMap(file)
+}
+
+// LoadMap replaces the terrain, edge overrides and portals with those of a map,
+// then schedules a single recompute. The map must match the navigator's grid
+// size; on error the navigator is left unchanged.
+func (f *FlowFieldNavigator) LoadMap(m *MapFile) error {
+	levels := max(m.Levels, 1)
+	if m.Width != f.config.GridWidth || m.Height != f.config.GridHeight || levels != f.config.levels() {
+		return errors.New("map size doesn't match navigator grid")
+	}
+
+	if m.Terrain != nil {
+		if len(m.Terrain) != m.Height*levels {
+			return errors.New("map terrain height doesn't match map size")
+		}
+		for _, row := range m.Terrain {
+			if len(row) != m.Width {
+				return errors.New("map terrain width doesn't match map size")
+			}
+			for _, cost := range row {
+				if cost < -1 {
+					return ErrInvalidCost
+				}
+			}
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// Apply the links to a scratch grid first so a bad one changes nothing
+	scratch := NewLayeredGrid(m.Width, m.Height, levels)
+	scratch.WrapX, scratch.WrapY = f.grid.WrapX, f.grid.WrapY
+	for _, spec := range m.Edges {
+		edge := spec.edge()
+		if !f.isStep(edge) {
+			return fmt.Errorf("%w: %v from (%d, %d)", ErrInvalidDirection, spec.Dir, spec.X, spec.Y)
+		}
+		if spec.Cost != nil {
+			if err := scratch.SetEdgeCost(edge, *spec.Cost); err != nil {
+				return err
+			}
+		}
+		if spec.OneWay {
+			if err := scratch.SetOneWay(edge.From, edge.Dir); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, portal := range m.Portals {
+		if err := scratch.AddPortal(portal); err != nil {
+			return err
+		}
+	}
+
+	for i := range f.grid.terrain {
+		f.grid.terrain[i] = 1
+	}
+	for y, row := range m.Terrain {
+		copy(f.grid.Terrain[y], row)
+	}
+	f.grid.edges = scratch.edges
+	f.grid.portals = scratch.portals
+	f.grid.RecomputeCosts()
+	f.publishCellsLocked()
+
+	return f.recomputeLocked()
+}
@@ -0,0 +1,144 @@
+package navigation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const gatesMap = "../data/maps/gates.json"
+
+func TestGatesMapRoundTrip(t *testing.T) {
+	m, err := LoadMapFile(gatesMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := LoadMap(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, decoded) {
+		t.Fatalf("round trip changed the map:\n%+v\n%+v", m, decoded)
+	}
+
+	navigator := newTestNavigator(t, EightWayConfig(m.Width, m.Height))
+	if err := navigator.LoadMap(decoded); err != nil {
+		t.Fatal(err)
+	}
+	view := navigator.View()
+
+	if cost := view.CostAt(Position{X: 3, Y: 0}); cost != -1 {
+		t.Errorf("wall cost = %d, want -1", cost)
+	}
+	if cost := view.CostAt(Position{X: 5, Y: 8}); cost != 3 {
+		t.Errorf("mud cost = %d, want 3", cost)
+	}
+	if cost, ok := view.EdgeCostAt(Edge{From: Position{X: 3, Y: 2}, Dir: Direction{X: -1}}); !ok || cost != BlockedEdge {
+		t.Errorf("gate reverse edge = %d, %v; want blocked", cost, ok)
+	}
+	if cost, ok := view.EdgeCostAt(Edge{From: Position{X: 0, Y: 8}, Dir: Direction{X: 1}}); !ok || cost != 1 {
+		t.Errorf("road edge = %d, %v; want 1", cost, ok)
+	}
+	portal, ok := view.PortalAt(Position{X: 1, Y: 9})
+	if want := (Portal{From: Position{X: 1, Y: 9}, To: Position{X: 8, Y: 0}, Cost: 2}); !ok || portal != want {
+		t.Errorf("portal = %+v, %v; want %+v", portal, ok, want)
+	}
+}
+
+func TestLoadMapRejectsBadLinks(t *testing.T) {
+	m, err := LoadMapFile(gatesMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	navigator := newTestNavigator(t, EightWayConfig(m.Width, m.Height))
+	if err := navigator.LoadMap(m); err != nil {
+		t.Fatal(err)
+	}
+
+	before := navigator.GetGrid()
+	cost := 1
+	tests := []struct {
+		name string
+		edit func(m *MapFile)
+		err  error
+	}{
+		{"edge leaving the grid", func(m *MapFile) {
+			m.Edges = append(m.Edges, EdgeSpec{X: 0, Y: 0, Dir: Direction{X: -1}, Cost: &cost})
+		}, ErrInvalidPosition},
+		{"edge that isn't a step", func(m *MapFile) {
+			m.Edges = append(m.Edges, EdgeSpec{X: 5, Y: 5, Dir: Direction{X: 2}, Cost: &cost})
+		}, ErrInvalidDirection},
+		{"portal onto itself", func(m *MapFile) {
+			m.Portals = append(m.Portals, Portal{From: Position{X: 5, Y: 5}, To: Position{X: 5, Y: 5}})
+		}, ErrInvalidPortal},
+		{"second portal from one cell", func(m *MapFile) {
+			m.Portals = append(m.Portals, Portal{From: Position{X: 1, Y: 9}, To: Position{X: 0, Y: 0}})
+		}, ErrPortalExists},
+	}
+
+	for _, test := range tests {
+		bad := &MapFile{
+			Width:   m.Width,
+			Height:  m.Height,
+			Terrain: make([][]int, m.Height),
+			Edges:   slices.Clone(m.Edges),
+			Portals: slices.Clone(m.Portals),
+		}
+		// Terrain a failed load must not apply
+		for y := range bad.Terrain {
+			bad.Terrain[y] = make([]int, m.Width)
+		}
+		test.edit(bad)
+
+		if err := navigator.LoadMap(bad); !errors.Is(err, test.err) {
+			t.Errorf("%s: err = %v, want %v", test.name, err, test.err)
+		}
+
+		after := navigator.GetGrid()
+		if !reflect.DeepEqual(before.Costs, after.Costs) {
+			t.Errorf("%s: costs changed", test.name)
+		}
+		if !maps.Equal(before.Edges(), after.Edges()) {
+			t.Errorf("%s: edges changed", test.name)
+		}
+		if !maps.Equal(before.Portals(), after.Portals()) {
+			t.Errorf("%s: portals changed", test.name)
+		}
+	}
+}
+
+func TestLoadMapEdgesOnUpperLevel(t *testing.T) {
+	m, err := LoadMap(strings.NewReader(`{
+		"width": 3, "height": 1, "levels": 2,
+		"edges": [{"x": 1, "y": 0, "level": 1, "dir": "left", "oneWay": true}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := EightWayConfig(3, 1)
+	config.Levels = 2
+	navigator := newTestNavigator(t, config)
+	if err := navigator.LoadMap(m); err != nil {
+		t.Fatal(err)
+	}
+
+	view := navigator.View()
+	upper := Edge{From: Position{X: 0, Y: 0, Level: 1}, Dir: Direction{X: 1}}
+	if cost, ok := view.EdgeCostAt(upper); !ok || cost != BlockedEdge {
+		t.Errorf("upper level reverse edge = %d, %v; want blocked", cost, ok)
+	}
+	if _, ok := view.EdgeCostAt(Edge{From: Position{X: 0, Y: 0}, Dir: Direction{X: 1}}); ok {
+		t.Error("edge was applied to level 0")
+	}
+}
@@ -0,0 +1,140 @@
+package navigation
+
+// UsePortal is the flow direction of a portal entrance whose best route is
+// through the portal. Units on such a cell should be moved to the portal's exit.
+var UsePortal = Direction{X: 2, Y: 2}
+
+// Portal links an entrance cell to an exit cell anywhere on the grid, like a
+// tunnel or teleport pad. Portals are one-way; link both cells for a
+// two-way tunnel.
+type Portal struct {
+	From Position `json:"from"` // Entrance
+	To   Position `json:"to"`   // Exit
+	Cost int      `json:"cost"` // Path cost of travelling through
+}
+
+// AddPortal links an entrance to an exit. Each cell can be the entrance of at
+// most one portal.
+func (g *Grid) AddPortal(portal Portal) error {
+	if !g.IsValidPosition(portal.From) || !g.IsValidPosition(portal.To) {
+		return ErrInvalidPosition
+	}
+	portal.From, portal.To = g.Wrap(portal.From), g.Wrap(portal.To)
+	if portal.From == portal.To {
+		return ErrInvalidPortal
+	}
+	if portal.Cost < 0 {
+		return ErrInvalidCost
+	}
+	if _, ok := g.portals[portal.From]; ok {
+		return ErrPortalExists
+	}
+
+	if g.portals == nil {
+		g.portals = make(map[Position]Portal)
+	}
+	g.portals[portal.From] = portal
+	return nil
+}
+
+// RemovePortal deletes the portal with the given entrance
+func (g *Grid) RemovePortal(from Position) error {
+	from = g.Wrap(from)
+	if _, ok := g.portals[from]; !ok {
+		return ErrPortalNotFound
+	}
+	delete(g.portals, from)
+	return nil
+}
+
+// PortalAt returns the portal whose entrance is at a position, if there is one
+func (g *Grid) PortalAt(pos Position) (Portal, bool) {
+	portal, ok := g.portals[g.Wrap(pos)]
+	return portal, ok
+}
+
+// Portals returns a copy of every portal, keyed by entrance
+func (g *Grid) Portals() map[Position]Portal {
+	portals := make(map[Position]Portal, len(g.portals))
+	for from, portal := range g.portals {
+		portals[from] = portal
+	}
+	return portals
+}
+
+// AddStairs links two cells, usually on different levels, with a portal each
+// way, like stairs or a ramp between floors
+func (g *Grid) AddStairs(a, b Position, cost int) error {
+	if err := g.AddPortal(Portal{From: a, To: b, Cost: cost}); err != nil {
+		return err
+	}
+	if err := g.AddPortal(Portal{From: b, To: a, Cost: cost}); err != nil {
+		delete(g.portals, g.Wrap(a))
+		return err
+	}
+	return nil
+}
+
+// AddPortal links an entrance to an exit and schedules a recompute
+func (f *FlowFieldNavigator) AddPortal(portal Portal) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.grid.AddPortal(portal); err != nil {
+		return err
+	}
+	f.publishCellsLocked()
+	return f.recomputeLocked()
+}
+
+// AddStairs links two cells with a portal each way and schedules a recompute
+func (f *FlowFieldNavigator) AddStairs(a, b Position, cost int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.grid.AddStairs(a, b, cost); err != nil {
+		return err
+	}
+	f.publishCellsLocked()
+	return f.recomputeLocked()
+}
+
+// RemovePortal deletes the portal with the given entrance and schedules a recompute
+func (f *FlowFieldNavigator) RemovePortal(from Position) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.grid.RemovePortal(from); err != nil {
+		return err
+	}
+	f.publishCellsLocked()
+	return f.recomputeLocked()
+}
+
+// PortalAt returns the portal whose entrance is at a position, if there is one
+func (f *FlowFieldNavigator) PortalAt(pos Position) (Portal, bool) {
+	return f.View().PortalAt(pos)
+}
+
+// Portals returns a copy of every portal, keyed by entrance
+func (f *FlowFieldNavigator) Portals() map[Position]Portal {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.grid.Portals()
+}
+
+// portalsByExit indexes portals by the flat index of their exit, so distances
+// can be propagated backwards through them
+func (f *FlowFieldNavigator) portalsByExit(portals map[Position]Portal) map[int][]Portal {
+	if len(portals) == 0 {
+		return nil
+	}
+
+	byExit := make(map[int][]Portal)
+	for _, portal := range portals {
+		exit := f.index(portal.To)
+		byExit[exit] = append(byExit[exit], portal)
+	}
+	return byExit
+}
@@ -0,0 +1,103 @@
+package navigation
+
+import (
+	"errors"
+	"testing"
+)
+
+// newWalledNavigator returns an 8x8 navigator whose goal at (7, 7) on level 0
+// is cut off from the left side of that level by a wall at x = 3
+func newWalledNavigator(t *testing.T, levels int) *FlowFieldNavigator {
+	t.Helper()
+	config := EightWayConfig(8, 8)
+	config.Levels = levels
+	navigator := newTestNavigator(t, config)
+	for y := range 8 {
+		if err := navigator.SetCellType(Position{X: 3, Y: y}, Obstacle); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := navigator.SetGoal(Position{X: 7, Y: 7}); err != nil {
+		t.Fatal(err)
+	}
+	return navigator
+}
+
+func TestPortalLeadsPastWall(t *testing.T) {
+	navigator := newWalledNavigator(t, 1)
+	entrance := Position{X: 0, Y: 0}
+	if err := navigator.AddPortal(Portal{From: entrance, To: Position{X: 7, Y: 0}, Cost: 1}); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+
+	if direction, err := navigator.GetFlowDirection(entrance); err != nil || direction != UsePortal {
+		t.Errorf("flow at entrance = %v, %v; want portal", direction, err)
+	}
+	if direction, err := navigator.GetFlowDirection(Position{X: 1, Y: 1}); err != nil || direction != (Direction{X: -1, Y: -1}) {
+		t.Errorf("flow beside entrance = %v, %v; want up-left", direction, err)
+	}
+}
+
+func TestUnreachablePortalIsNotTaken(t *testing.T) {
+	navigator := newWalledNavigator(t, 2)
+
+	// Stairs join the walled-off side of level 0 to level 1, but neither
+	// end can reach the goal
+	lower, upper := Position{X: 0, Y: 0}, Position{X: 0, Y: 0, Level: 1}
+	if err := navigator.AddStairs(lower, upper, 1); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+
+	for _, pos := range []Position{lower, upper} {
+		if direction, err := navigator.GetFlowDirection(pos); !errors.Is(err, ErrNoPath) {
+			t.Errorf("flow at %v = %v, %v; want ErrNoPath", pos, direction, err)
+		}
+		if vector, err := navigator.GetFlowVector(pos); !errors.Is(err, ErrNoPath) {
+			t.Errorf("flow vector at %v = %v, %v; want ErrNoPath", pos, vector, err)
+		}
+		if direction := navigator.View().FlowAt(pos); direction != (Direction{}) {
+			t.Errorf("view flow at %v = %v, want none", pos, direction)
+		}
+	}
+}
+
+func TestZeroCostPortalPairDoesNotLoop(t *testing.T) {
+	tests := []struct {
+		name string
+		link func(navigator *FlowFieldNavigator, a, b Position) error
+	}{
+		{"portal pair", func(navigator *FlowFieldNavigator, a, b Position) error {
+			if err := navigator.AddPortal(Portal{From: a, To: b}); err != nil {
+				return err
+			}
+			return navigator.AddPortal(Portal{From: b, To: a})
+		}},
+		{"stairs", func(navigator *FlowFieldNavigator, a, b Position) error {
+			return navigator.AddStairs(a, b, 0)
+		}},
+	}
+
+	for _, test := range tests {
+		config := EightWayConfig(8, 1)
+		config.Directions = FourWayDirections
+		navigator := newTestNavigator(t, config)
+		a, b := Position{X: 0}, Position{X: 4}
+		if err := test.link(navigator, a, b); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if err := navigator.SetGoal(Position{X: 7}); err != nil {
+			t.Fatal(err)
+		}
+		navigator.Wait()
+
+		// The far end walks; the near end may jump to it, but never both
+		if direction, err := navigator.GetFlowDirection(b); err != nil || direction != (Direction{X: 1}) {
+			t.Errorf("%s: flow at %v = %v, %v; want right", test.name, b, direction, err)
+		}
+		if direction, err := navigator.GetFlowDirection(a); err != nil || direction != UsePortal {
+			t.Errorf("%s: flow at %v = %v, %v; want portal", test.name, a, direction, err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package navigation
+
+// FieldProfile describes an alternative flow field toward the same goal
+type FieldProfile struct {
+	// LayerWeights scales the costs of the named overlay layers. Listed layers
+	// apply even when disabled; a weight of zero ignores the layer entirely.
+	LayerWeights map[string]float64
+
+	// Movement selects whether obstacles block the field (Ground) or are flown over (Air)
+	Movement MovementLayer
+}
+
+// AirProfile is the built-in profile used for air units
+const AirProfile = "air"
+
+// profileField holds a flow field computed for a profile
+type profileField struct {
+	costs []int // Flat row-major, like the grid
+	flowData
+}
+
+// AddProfile registers a profile and schedules its flow field to be computed
+func (f *FlowFieldNavigator) AddProfile(name string, profile FieldProfile) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.profiles[name]; ok {
+		return ErrProfileExists
+	}
+
+	weights := make(map[string]float64, len(profile.LayerWeights))
+	for layer, weight := range profile.LayerWeights {
+		weights[layer] = weight
+	}
+	f.profiles[name] = FieldProfile{LayerWeights: weights, Movement: profile.Movement}
+
+	if f.isGoalSet {
+		f.requestLocked()
+	}
+
+	return nil
+}
+
+// RemoveProfile deletes a profile. Its flow field stays readable until the
+// next recompute is published.
+func (f *FlowFieldNavigator) RemoveProfile(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.profiles[name]; !ok {
+		return ErrProfileNotFound
+	}
+	delete(f.profiles, name)
+	return nil
+}
+
+// HasProfile reports whether a profile with the given name is registered
+func (f *FlowFieldNavigator) HasProfile(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.profiles[name]
+	return ok
+}
+
+// GetProfileFlowDirection returns the optimal direction from a position using a
+// profile's published field. A newly added profile reports ErrProfileNotFound
+// until its first field is published.
+func (f *FlowFieldNavigator) GetProfileFlowDirection(name string, pos Position) (Direction, error) {
+	field := f.field.Load()
+	if !field.isGoalSet {
+		return Direction{}, ErrInvalidGoal
+	}
+
+	profile, ok := field.profiles[name]
+	if !ok {
+		return Direction{}, ErrProfileNotFound
+	}
+
+	return f.lookupFlow(field, profile.flowField, pos)
+}
+
+// GetProfileFlowVector returns the smooth unit direction from a position using a profile's field
+func (f *FlowFieldNavigator) GetProfileFlowVector(name string, pos Position) (Vector, error) {
+	field := f.field.Load()
+	if !field.isGoalSet {
+		return Vector{}, ErrInvalidGoal
+	}
+
+	profile, ok := field.profiles[name]
+	if !ok {
+		return Vector{}, ErrProfileNotFound
+	}
+
+	return f.lookupVector(field, profile.flowData, pos)
+}
+
+// GetMovementFlowDirection returns the optimal direction from a position for a movement layer
+func (f *FlowFieldNavigator) GetMovementFlowDirection(movement MovementLayer, pos Position) (Direction, error) {
+	switch movement {
+	case Ground:
+		return f.GetFlowDirection(pos)
+	case Air:
+		return f.GetProfileFlowDirection(AirProfile, pos)
+	default:
+		return Direction{}, ErrInvalidMovementLayer
+	}
+}
+
+// GetMovementFlowVector returns the smooth unit direction from a position for a movement layer
+func (f *FlowFieldNavigator) GetMovementFlowVector(movement MovementLayer, pos Position) (Vector, error) {
+	switch movement {
+	case Ground:
+		return f.GetFlowVector(pos)
+	case Air:
+		return f.GetProfileFlowVector(AirProfile, pos)
+	default:
+		return Vector{}, ErrInvalidMovementLayer
+	}
+}
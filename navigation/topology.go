@@ -0,0 +1,126 @@
+package navigation
+
+import "math"
+
+// Topology describes how grid cells connect and where they sit in space.
+// Cells are always stored in a rectangular Width x Height array; a topology
+// decides which stored cells neighbour each other.
+type Topology interface {
+	// Neighbors returns the steps from a cell to each of its neighbours
+	Neighbors(pos Position) []Direction
+	// StepCost returns the cost multiplier for taking a step
+	StepCost(dir Direction) float64
+	// Center returns a cell's centre in layout units, with the grid's top-left
+	// corner at the origin
+	Center(pos Position) Vector
+	// CellAt returns the cell containing a point in layout units
+	CellAt(point Vector) Position
+}
+
+// SquareTopology connects square cells through a fixed set of directions.
+// Layout units are cell widths.
+type SquareTopology struct {
+	Directions   []Direction
+	DiagonalCost float64
+}
+
+// Neighbors returns the configured directions, which are the same for every cell
+func (t SquareTopology) Neighbors(Position) []Direction {
+	return t.Directions
+}
+
+// StepCost returns DiagonalCost for diagonal steps and 1 otherwise
+func (t SquareTopology) StepCost(dir Direction) float64 {
+	if dir.X != 0 && dir.Y != 0 {
+		return t.DiagonalCost
+	}
+	return 1
+}
+
+// Center returns the middle of the cell
+func (t SquareTopology) Center(pos Position) Vector {
+	return Vector{X: float64(pos.X) + 0.5, Y: float64(pos.Y) + 0.5}
+}
+
+// CellAt returns the cell whose square contains the point
+func (t SquareTopology) CellAt(point Vector) Position {
+	return Position{X: int(math.Floor(point.X)), Y: int(math.Floor(point.Y))}
+}
+
+// HexTopology connects pointy-top hexagons with six neighbours each. Cells are
+// stored in "odd-r" offset coordinates, where odd rows are shifted half a cell
+// right; HexAxial and HexOffset convert to and from axial coordinates. Layout
+// units are the hexagon's circumradius.
+type HexTopology struct{}
+
+// hexAxialDirections are the six neighbour steps in axial coordinates
+var hexAxialDirections = []Direction{
+	{X: 1, Y: 0}, {X: 1, Y: -1}, {X: 0, Y: -1},
+	{X: -1, Y: 0}, {X: -1, Y: 1}, {X: 0, Y: 1},
+}
+
+// hexOffsetDirections holds the offset-coordinate neighbour steps for even and odd rows
+var hexOffsetDirections = [2][]Direction{hexRowSteps(0), hexRowSteps(1)}
+
+// hexRowSteps converts the axial neighbour steps to offset steps for a row parity
+func hexRowSteps(parity int) []Direction {
+	origin := Position{X: 0, Y: parity}
+	q, r := HexAxial(origin)
+
+	steps := make([]Direction, len(hexAxialDirections))
+	for i, dir := range hexAxialDirections {
+		neighbor := HexOffset(q+dir.X, r+dir.Y)
+		steps[i] = Direction{X: neighbor.X - origin.X, Y: neighbor.Y - origin.Y}
+	}
+	return steps
+}
+
+// HexAxial converts an odd-r offset position to axial coordinates (q, r)
+func HexAxial(pos Position) (q, r int) {
+	return pos.X - (pos.Y-(pos.Y&1))/2, pos.Y
+}
+
+// HexOffset converts axial coordinates (q, r) to an odd-r offset position
+func HexOffset(q, r int) Position {
+	return Position{X: q + (r-(r&1))/2, Y: r}
+}
+
+// Neighbors returns the six offset steps for the cell's row parity
+func (HexTopology) Neighbors(pos Position) []Direction {
+	return hexOffsetDirections[pos.Y&1]
+}
+
+// StepCost returns 1, since every hex neighbour is equally far away
+func (HexTopology) StepCost(Direction) float64 {
+	return 1
+}
+
+// Center returns the middle of the hexagon
+func (HexTopology) Center(pos Position) Vector {
+	return Vector{
+		X: math.Sqrt(3) * (float64(pos.X) + 0.5*float64(pos.Y&1) + 0.5),
+		Y: 1.5*float64(pos.Y) + 1,
+	}
+}
+
+// CellAt returns the hexagon containing the point, rounding in cube coordinates
+func (HexTopology) CellAt(point Vector) Position {
+	x := point.X - math.Sqrt(3)/2
+	y := point.Y - 1
+
+	// Fractional axial coordinates, then cube rounding
+	q := math.Sqrt(3)/3*x - y/3
+	r := 2.0 / 3 * y
+	s := -q - r
+
+	roundQ, roundR, roundS := math.Round(q), math.Round(r), math.Round(s)
+	diffQ, diffR, diffS := math.Abs(roundQ-q), math.Abs(roundR-r), math.Abs(roundS-s)
+	switch {
+	case diffQ > diffR && diffQ > diffS:
+		roundQ = -roundR - roundS
+	case diffR > diffS:
+		roundR = -roundQ - roundS
+	}
+
+	return HexOffset(int(roundQ), int(roundR))
+}
@@ -1,8 +1,21 @@
 package navigation
 
-// Position represents a grid coordinate position
+import (
+	"fmt"
+	"math"
+)
+
+// Position represents a grid coordinate position. Level selects the floor on
+// multi-level grids and is 0 on single-level ones.
 type Position struct {
-	X, Y int
+	X     int `json:"x"`
+	Y     int `json:"y"`
+	Level int `json:"level,omitempty"`
+}
+
+// step returns the position one step away in dir on the same level
+func (p Position) step(dir Direction) Position {
+	return Position{X: p.X + dir.X, Y: p.Y + dir.Y, Level: p.Level}
 }
 
 // Direction represents a movement direction vector
@@ -10,16 +23,70 @@ type Direction struct {
 	X, Y int
 }
 
+// Vector is a continuous movement direction, used for smooth flow
+type Vector struct {
+	X, Y float64
+}
+
+// Normalized returns the vector scaled to unit length, or the zero vector
+func (v Vector) Normalized() Vector {
+	length := math.Hypot(v.X, v.Y)
+	if length == 0 {
+		return Vector{}
+	}
+	return Vector{X: v.X / length, Y: v.Y / length}
+}
+
 // CellType represents the type of a grid cell
 type CellType int
 
 const (
-	Passable CellType = iota
-	Obstacle
-	Goal
-	Building
+	Passable CellType = iota // Open cell, costed by terrain and overlays
+	Obstacle                 // Blocked by terrain or a non-building overlay
+	Goal                     // The navigator's goal cell
+	Building                 // Blocked by the buildings layer
 )
 
+// MovementLayer identifies how a unit traverses the grid
+type MovementLayer int
+
+const (
+	// Ground units are blocked by obstacles and buildings
+	Ground MovementLayer = iota
+	// Air units fly over obstacles and buildings
+	Air
+)
+
+// String returns the lowercase name of the movement layer
+func (m MovementLayer) String() string {
+	switch m {
+	case Ground:
+		return "ground"
+	case Air:
+		return "air"
+	default:
+		return fmt.Sprintf("MovementLayer(%d)", int(m))
+	}
+}
+
+// MarshalText encodes the movement layer by name
+func (m MovementLayer) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes a movement layer from its name
+func (m *MovementLayer) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "ground":
+		*m = Ground
+	case "air":
+		*m = Air
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidMovementLayer, text)
+	}
+	return nil
+}
+
 // Standard direction sets for different movement patterns
 var (
 	// FourWayDirections allows only cardinal movement (up, down, left, right)
@@ -43,46 +110,192 @@ var (
 	}
 )
 
-// Grid represents the navigation grid with costs
+// directionNames maps the eight unit steps to their names
+var directionNames = map[Direction]string{
+	{X: 0, Y: -1}:  "up",
+	{X: 0, Y: 1}:   "down",
+	{X: -1, Y: 0}:  "left",
+	{X: 1, Y: 0}:   "right",
+	{X: -1, Y: -1}: "up-left",
+	{X: -1, Y: 1}:  "down-left",
+	{X: 1, Y: -1}:  "up-right",
+	{X: 1, Y: 1}:   "down-right",
+}
+
+// String returns the name of a unit step or UsePortal, or its components otherwise
+func (d Direction) String() string {
+	if name, ok := directionNames[d]; ok {
+		return name
+	}
+	if d == UsePortal {
+		return "portal"
+	}
+	return fmt.Sprintf("Direction(%d, %d)", d.X, d.Y)
+}
+
+// MarshalText encodes a unit step by name
+func (d Direction) MarshalText() ([]byte, error) {
+	name, ok := directionNames[d]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDirection, d)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a unit step from its name
+func (d *Direction) UnmarshalText(text []byte) error {
+	for dir, name := range directionNames {
+		if name == string(text) {
+			*d = dir
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrInvalidDirection, text)
+}
+
+// Grid represents the navigation grid with costs. Cell data is stored flat in
+// row-major order with levels one after another, so cell (x, y) on level l
+// lives at index (l*Height+y)*Width+x. The [][] fields are row views into the
+// same storage, kept for existing callers.
 type Grid struct {
 	Width, Height int
-	Costs         [][]int  // -1 for obstacles, positive values for movement cost
+	Levels        int     // Stacked floors, each Width x Height
+	WrapX, WrapY  bool    // Whether positions wrap around on each axis
+	Terrain       [][]int // Base terrain costs before overlays are applied
+	Costs         [][]int // Effective costs: -1 for obstacles, positive values for movement cost
 	FlowField     [][]Direction
-	Distances     [][]int
+	Distances     [][]float64 // Path cost to the goal, +Inf where unreachable
 	CellTypes     [][]CellType
+
+	terrain   []int
+	costs     []int
+	flowField []Direction
+	distances []float64
+	cellTypes []CellType
+	goal      int // Flat index of the goal cell, -1 if none is marked
+
+	layers  []*CostLayer        // Overlay layers composed over the terrain in order
+	edges   map[Edge]int        // Per-step cost overrides, keyed by departing cell and direction
+	portals map[Position]Portal // Links to distant cells, keyed by entrance
 }
 
-// NewGrid creates a new navigation grid with the specified dimensions
+// NewGrid creates a new single-level navigation grid with the specified dimensions
 func NewGrid(width, height int) *Grid {
+	return NewLayeredGrid(width, height, 1)
+}
+
+// NewLayeredGrid creates a navigation grid with several stacked levels. Levels
+// are stored one after another, so the [][] row views hold every level's rows
+// in turn, Height rows per level.
+func NewLayeredGrid(width, height, levels int) *Grid {
+	size := width * height * levels
 	grid := &Grid{
 		Width:     width,
 		Height:    height,
-		Costs:     make([][]int, height),
-		FlowField: make([][]Direction, height),
-		Distances: make([][]int, height),
-		CellTypes: make([][]CellType, height),
-	}
-
-	// Initialize all slices
-	for y := 0; y < height; y++ {
-		grid.Costs[y] = make([]int, width)
-		grid.FlowField[y] = make([]Direction, width)
-		grid.Distances[y] = make([]int, width)
-		grid.CellTypes[y] = make([]CellType, width)
-		
-		// Initialize with passable terrain (cost = 1)
-		for x := 0; x < width; x++ {
-			grid.Costs[y][x] = 1
-			grid.CellTypes[y][x] = Passable
-		}
+		Levels:    levels,
+		terrain:   make([]int, size),
+		costs:     make([]int, size),
+		flowField: make([]Direction, size),
+		distances: make([]float64, size),
+		cellTypes: make([]CellType, size),
+		goal:      -1,
 	}
 
+	// Initialize with passable terrain (cost = 1)
+	for i := range size {
+		grid.terrain[i] = 1
+		grid.costs[i] = 1
+		grid.cellTypes[i] = Passable
+	}
+
+	rows := height * levels
+	grid.Terrain = rowViews(grid.terrain, width, rows)
+	grid.Costs = rowViews(grid.costs, width, rows)
+	grid.FlowField = rowViews(grid.flowField, width, rows)
+	grid.Distances = rowViews(grid.distances, width, rows)
+	grid.CellTypes = rowViews(grid.cellTypes, width, rows)
+
 	return grid
 }
 
-// IsValidPosition checks if a position is within grid bounds
+// rowViews returns a [][]T whose rows slice into flat row-major data
+func rowViews[T any](data []T, width, height int) [][]T {
+	rows := make([][]T, height)
+	for y := range height {
+		rows[y] = data[y*width : (y+1)*width : (y+1)*width]
+	}
+	return rows
+}
+
+// Wrap returns a position with its coordinates wrapped onto the grid on
+// wrapping axes, leaving other coordinates unchanged
+func (g *Grid) Wrap(pos Position) Position {
+	return wrapPosition(pos, g.Width, g.Height, g.WrapX, g.WrapY)
+}
+
+// Index returns the flat row-major index of a position, wrapping it first
+func (g *Grid) Index(pos Position) int {
+	return cellIndex(g.Wrap(pos), g.Width, g.Height)
+}
+
+// wrapPosition wraps a position's coordinates into [0, width) and [0, height)
+// on the axes that wrap
+func wrapPosition(pos Position, width, height int, wrapX, wrapY bool) Position {
+	if wrapX {
+		pos.X = ((pos.X % width) + width) % width
+	}
+	if wrapY {
+		pos.Y = ((pos.Y % height) + height) % height
+	}
+	return pos
+}
+
+// PositionOf returns the position of a flat row-major index
+func (g *Grid) PositionOf(index int) Position {
+	return cellPosition(index, g.Width, g.Height)
+}
+
+// cellIndex returns the flat index of a position in level-major, then
+// row-major order
+func cellIndex(pos Position, width, height int) int {
+	return (pos.Level*height+pos.Y)*width + pos.X
+}
+
+// cellPosition returns the position of a flat level-major, row-major index
+func cellPosition(index, width, height int) Position {
+	return Position{X: index % width, Y: index / width % height, Level: index / (width * height)}
+}
+
+// TerrainData returns the flat row-major terrain costs, shared with the grid
+func (g *Grid) TerrainData() []int {
+	return g.terrain
+}
+
+// CostData returns the flat row-major effective costs, shared with the grid
+func (g *Grid) CostData() []int {
+	return g.costs
+}
+
+// FlowData returns the flat row-major flow field, shared with the grid
+func (g *Grid) FlowData() []Direction {
+	return g.flowField
+}
+
+// DistanceData returns the flat row-major distances, shared with the grid
+func (g *Grid) DistanceData() []float64 {
+	return g.distances
+}
+
+// CellTypeData returns the flat row-major cell types, shared with the grid
+func (g *Grid) CellTypeData() []CellType {
+	return g.cellTypes
+}
+
+// IsValidPosition checks if a position is within grid bounds. Every
+// coordinate is valid on a wrapping axis.
 func (g *Grid) IsValidPosition(pos Position) bool {
-	return pos.X >= 0 && pos.X < g.Width && pos.Y >= 0 && pos.Y < g.Height
+	pos = g.Wrap(pos)
+	return pos.X >= 0 && pos.X < g.Width && pos.Y >= 0 && pos.Y < g.Height && pos.Level >= 0 && pos.Level < g.Levels
 }
 
 // IsPassable checks if a position is passable (not an obstacle)
@@ -90,30 +303,92 @@ func (g *Grid) IsPassable(pos Position) bool {
 	if !g.IsValidPosition(pos) {
 		return false
 	}
-	return g.Costs[pos.Y][pos.X] != -1
+	return g.costs[g.Index(pos)] != -1
 }
 
-// SetObstacle marks a position as an obstacle
+// SetObstacle marks a terrain position as an obstacle
 func (g *Grid) SetObstacle(pos Position) error {
 	if !g.IsValidPosition(pos) {
 		return ErrInvalidPosition
 	}
-	g.Costs[pos.Y][pos.X] = -1
-	g.CellTypes[pos.Y][pos.X] = Obstacle
+	i := g.Index(pos)
+	g.terrain[i] = -1
+	g.recomputeCell(i)
 	return nil
 }
 
-// SetBuilding marks a position as a building
+// SetBuilding marks a position as a building on the buildings layer
 func (g *Grid) SetBuilding(pos Position) error {
 	if !g.IsValidPosition(pos) {
 		return ErrInvalidPosition
 	}
-	g.Costs[pos.Y][pos.X] = -1
-	g.CellTypes[pos.Y][pos.X] = Building
+	layer, ok := g.Layer(LayerBuildings)
+	if !ok {
+		layer, _ = g.AddLayer(LayerBuildings, CombineMax)
+	}
+	i := g.Index(pos)
+	layer.costs[i] = -1
+	g.recomputeCell(i)
 	return nil
 }
 
-// SetCost sets the movement cost for a position
+// SetCellType changes a cell's type along with the costs behind it:
+//   - Passable clears a terrain obstacle back to cost 1 and removes any building
+//   - Obstacle blocks the terrain
+//   - Building blocks the cell on the buildings layer, keeping its terrain
+//   - Goal marks the cell as the goal, which must be passable
+func (g *Grid) SetCellType(pos Position, cellType CellType) error {
+	if !g.IsValidPosition(pos) {
+		return ErrInvalidPosition
+	}
+	i := g.Index(pos)
+
+	switch cellType {
+	case Passable:
+		if g.terrain[i] == -1 {
+			g.terrain[i] = 1
+		}
+		if layer, ok := g.Layer(LayerBuildings); ok && layer.costs[i] == -1 {
+			layer.costs[i] = 0
+		}
+		g.recomputeCell(i)
+		return nil
+	case Obstacle:
+		return g.SetObstacle(pos)
+	case Building:
+		return g.SetBuilding(pos)
+	case Goal:
+		if g.costs[i] == -1 {
+			return ErrInvalidGoal
+		}
+		g.setGoal(i)
+		return nil
+	default:
+		return ErrInvalidCellType
+	}
+}
+
+// CellTypeAt returns the type of a cell
+func (g *Grid) CellTypeAt(pos Position) (CellType, error) {
+	if !g.IsValidPosition(pos) {
+		return Passable, ErrInvalidPosition
+	}
+	return g.cellTypes[g.Index(pos)], nil
+}
+
+// setGoal moves the goal marker to a flat index, or clears it for -1
+func (g *Grid) setGoal(i int) {
+	previous := g.goal
+	g.goal = i
+	if previous >= 0 {
+		g.recomputeCell(previous)
+	}
+	if i >= 0 {
+		g.recomputeCell(i)
+	}
+}
+
+// SetCost sets the terrain movement cost for a position
 func (g *Grid) SetCost(pos Position, cost int) error {
 	if !g.IsValidPosition(pos) {
 		return ErrInvalidPosition
@@ -121,7 +396,9 @@ func (g *Grid) SetCost(pos Position, cost int) error {
 	if cost < 0 {
 		return ErrInvalidCost
 	}
-	g.Costs[pos.Y][pos.X] = cost
+	i := g.Index(pos)
+	g.terrain[i] = cost
+	g.recomputeCell(i)
 	return nil
 }
 
@@ -130,5 +407,5 @@ func (g *Grid) GetFlowDirection(pos Position) (Direction, error) {
 	if !g.IsValidPosition(pos) {
 		return Direction{}, ErrInvalidPosition
 	}
-	return g.FlowField[pos.Y][pos.X], nil
-}
\ No newline at end of file
+	return g.flowField[g.Index(pos)], nil
+}
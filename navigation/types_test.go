@@ -0,0 +1,141 @@
+package navigation
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGridSetCellType(t *testing.T) {
+	pos := Position{X: 2, Y: 1}
+	tests := []struct {
+		cellType CellType
+		cost     int
+	}{
+		{Passable, 1},
+		{Obstacle, -1},
+		{Building, -1},
+		{Goal, 1},
+	}
+
+	for _, test := range tests {
+		grid := NewGrid(4, 3)
+		for i := range grid.terrain {
+			grid.terrain[i] = 1
+		}
+		grid.RecomputeCosts()
+
+		if err := grid.SetCellType(pos, test.cellType); err != nil {
+			t.Fatalf("SetCellType(%v): %v", test.cellType, err)
+		}
+		if cellType, _ := grid.CellTypeAt(pos); cellType != test.cellType {
+			t.Errorf("SetCellType(%v): cell type = %v", test.cellType, cellType)
+		}
+		if cost := grid.Costs[pos.Y][pos.X]; cost != test.cost {
+			t.Errorf("SetCellType(%v): cost = %d, want %d", test.cellType, cost, test.cost)
+		}
+	}
+}
+
+func TestGridSetCellTypeErrors(t *testing.T) {
+	grid := NewGrid(3, 3)
+	if err := grid.SetCellType(Position{X: 3}, Passable); !errors.Is(err, ErrInvalidPosition) {
+		t.Errorf("outside grid: err = %v, want ErrInvalidPosition", err)
+	}
+	if err := grid.SetCellType(Position{}, CellType(99)); !errors.Is(err, ErrInvalidCellType) {
+		t.Errorf("unknown type: err = %v, want ErrInvalidCellType", err)
+	}
+
+	if err := grid.SetObstacle(Position{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := grid.SetCellType(Position{}, Goal); !errors.Is(err, ErrInvalidGoal) {
+		t.Errorf("goal on obstacle: err = %v, want ErrInvalidGoal", err)
+	}
+}
+
+func TestPassableClearsBuilding(t *testing.T) {
+	grid := NewGrid(3, 3)
+	pos := Position{X: 1, Y: 1}
+	if err := grid.SetCellType(pos, Building); err != nil {
+		t.Fatal(err)
+	}
+	if err := grid.SetCellType(pos, Passable); err != nil {
+		t.Fatal(err)
+	}
+
+	if cellType, _ := grid.CellTypeAt(pos); cellType != Passable {
+		t.Errorf("cell type = %v, want Passable", cellType)
+	}
+	if !grid.IsPassable(pos) {
+		t.Error("cell is still blocked")
+	}
+	if layer, _ := grid.Layer(LayerBuildings); layer.At(pos) != 0 {
+		t.Errorf("buildings layer = %d, want 0", layer.At(pos))
+	}
+}
+
+func TestClassifyCellBlockingLayers(t *testing.T) {
+	grid := NewGrid(3, 1)
+	buildings, _ := grid.AddLayer(LayerBuildings, CombineMax)
+	walls, _ := grid.AddLayer("walls", CombineMax)
+
+	if err := buildings.Set(Position{X: 0}, -1); err != nil {
+		t.Fatal(err)
+	}
+	if err := walls.Set(Position{X: 1}, -1); err != nil {
+		t.Fatal(err)
+	}
+	grid.RecomputeCosts()
+
+	want := []CellType{Building, Obstacle, Passable}
+	for x, cellType := range want {
+		if got := grid.classifyCell(x); got != cellType {
+			t.Errorf("classifyCell(%d) = %v, want %v", x, got, cellType)
+		}
+	}
+
+	// Disabled layers don't block, so they don't decide the type either
+	walls.Enabled = false
+	grid.RecomputeCosts()
+	if got, _ := grid.CellTypeAt(Position{X: 1}); got != Passable {
+		t.Errorf("cell under disabled layer = %v, want Passable", got)
+	}
+}
+
+func TestNavigatorSetCellType(t *testing.T) {
+	navigator := newTestNavigator(t, EightWayConfig(5, 5))
+	goal := Position{X: 4, Y: 4}
+	if err := navigator.SetCellType(goal, Goal); err != nil {
+		t.Fatal(err)
+	}
+	if got := navigator.GetGoal(); got != goal {
+		t.Fatalf("goal = %v, want %v", got, goal)
+	}
+
+	for x, cellType := range []CellType{Obstacle, Building, Passable} {
+		pos := Position{X: x, Y: 1}
+		if err := navigator.SetCellType(pos, cellType); err != nil {
+			t.Fatalf("SetCellType(%v): %v", cellType, err)
+		}
+		if got := navigator.View().CellTypeAt(pos); got != cellType {
+			t.Errorf("SetCellType(%v): view cell type = %v", cellType, got)
+		}
+	}
+
+	// A building keeps the terrain under it, so blocked terrain still wins
+	if err := navigator.SetCellType(Position{X: 0, Y: 1}, Building); err != nil {
+		t.Fatal(err)
+	}
+	if got := navigator.View().CellTypeAt(Position{X: 0, Y: 1}); got != Obstacle {
+		t.Errorf("building on obstacle: cell type = %v, want Obstacle", got)
+	}
+
+	// Blocking the goal clears it
+	if err := navigator.SetCellType(goal, Obstacle); !errors.Is(err, ErrInvalidGoal) {
+		t.Fatalf("obstacle on goal: err = %v, want ErrInvalidGoal", err)
+	}
+	navigator.Wait()
+	if _, ok := navigator.View().Goal(); ok {
+		t.Error("goal is still set after being blocked")
+	}
+}
@@ -0,0 +1,167 @@
+package navigation
+
+import "math"
+
+// GridView is a read-only view of the navigator's cells and published flow
+// field. Views share the navigator's immutable snapshots instead of copying
+// them, so they are cheap to take and safe to read from any goroutine.
+type GridView struct {
+	width, height, levels int
+	wrapX, wrapY          bool
+	cells                 *cellSet
+	field                 *fieldSet
+}
+
+// View returns a read-only view of the current costs and cell types and the
+// latest published flow field
+func (f *FlowFieldNavigator) View() GridView {
+	return GridView{
+		width:  f.config.GridWidth,
+		height: f.config.GridHeight,
+		levels: f.config.levels(),
+		wrapX:  f.config.WrapX,
+		wrapY:  f.config.WrapY,
+		cells:  f.cells.Load(),
+		field:  f.field.Load(),
+	}
+}
+
+// CostAt returns the effective movement cost of a cell, or -1 outside the grid
+func (f *FlowFieldNavigator) CostAt(pos Position) int {
+	return f.View().CostAt(pos)
+}
+
+// DistanceAt returns the published distance from a cell to the goal, or +Inf
+// if the cell is outside the grid or unreachable
+func (f *FlowFieldNavigator) DistanceAt(pos Position) float64 {
+	return f.View().DistanceAt(pos)
+}
+
+// Width returns the grid width
+func (v GridView) Width() int {
+	return v.width
+}
+
+// Height returns the grid height
+func (v GridView) Height() int {
+	return v.height
+}
+
+// Levels returns the number of grid levels
+func (v GridView) Levels() int {
+	return v.levels
+}
+
+// IsValidPosition checks if a position is within grid bounds. Every
+// coordinate is valid on a wrapping axis.
+func (v GridView) IsValidPosition(pos Position) bool {
+	_, ok := v.index(pos)
+	return ok
+}
+
+// Wrap wraps a position onto the grid on the wrapping axes, leaving other
+// coordinates as they are
+func (v GridView) Wrap(pos Position) Position {
+	return wrapPosition(pos, v.width, v.height, v.wrapX, v.wrapY)
+}
+
+// index returns the flat index of a position, wrapped onto the grid, and
+// whether it is within bounds
+func (v GridView) index(pos Position) (int, bool) {
+	pos = v.Wrap(pos)
+	if pos.X < 0 || pos.X >= v.width || pos.Y < 0 || pos.Y >= v.height || pos.Level < 0 || pos.Level >= v.levels {
+		return 0, false
+	}
+	return cellIndex(pos, v.width, v.height), true
+}
+
+// IsPassable checks if a position can be traversed
+func (v GridView) IsPassable(pos Position) bool {
+	return v.CostAt(pos) != -1
+}
+
+// CostAt returns the effective movement cost of a cell, or -1 outside the grid
+func (v GridView) CostAt(pos Position) int {
+	i, ok := v.index(pos)
+	if !ok {
+		return -1
+	}
+	return v.cells.costs[i]
+}
+
+// CellTypeAt returns the type of a cell, or Obstacle outside the grid
+func (v GridView) CellTypeAt(pos Position) CellType {
+	i, ok := v.index(pos)
+	if !ok {
+		return Obstacle
+	}
+	return v.cells.cellTypes[i]
+}
+
+// EdgeCostAt returns the override for a step, if there is one
+func (v GridView) EdgeCostAt(edge Edge) (int, bool) {
+	edge.From = v.Wrap(edge.From)
+	cost, ok := v.cells.edges[edge]
+	return cost, ok
+}
+
+// PortalAt returns the portal whose entrance is at a position, if there is one
+func (v GridView) PortalAt(pos Position) (Portal, bool) {
+	portal, ok := v.cells.portals[v.Wrap(pos)]
+	return portal, ok
+}
+
+// DistanceAt returns the distance from a cell to the goal, or +Inf if the cell
+// is outside the grid, unreachable or no field is published yet
+func (v GridView) DistanceAt(pos Position) float64 {
+	i, ok := v.index(pos)
+	if !ok || v.field.distances == nil {
+		return math.Inf(1)
+	}
+	return v.field.distances[i]
+}
+
+// FlowAt returns the flow direction of a cell, or a zero direction if none is known
+func (v GridView) FlowAt(pos Position) Direction {
+	i, ok := v.index(pos)
+	if !ok || v.field.flowField == nil {
+		return Direction{}
+	}
+	return v.field.flowField[i]
+}
+
+// FlowVectorAt returns the smooth flow vector of a cell, or a zero vector if none is known
+func (v GridView) FlowVectorAt(pos Position) Vector {
+	i, ok := v.index(pos)
+	if !ok || v.field.flowVectors == nil {
+		return Vector{}
+	}
+	return v.field.flowVectors[i]
+}
+
+// ProfileFlowAt returns the flow direction of a cell in a profile's field, or a
+// zero direction if none is known
+func (v GridView) ProfileFlowAt(name string, pos Position) Direction {
+	profile, ok := v.field.profiles[name]
+	i, valid := v.index(pos)
+	if !ok || !valid || profile.flowField == nil {
+		return Direction{}
+	}
+	return profile.flowField[i]
+}
+
+// ProfileFlowVectorAt returns the smooth flow vector of a cell in a profile's
+// field, or a zero vector if none is known
+func (v GridView) ProfileFlowVectorAt(name string, pos Position) Vector {
+	profile, ok := v.field.profiles[name]
+	i, valid := v.index(pos)
+	if !ok || !valid || profile.flowVectors == nil {
+		return Vector{}
+	}
+	return profile.flowVectors[i]
+}
+
+// Goal returns the goal of the published flow field and whether one is set
+func (v GridView) Goal() (Position, bool) {
+	return v.field.goal, v.field.isGoalSet
+}
@@ -1,86 +1,348 @@
 package systems
 
 import (
+	"log"
+	"math"
+	"slices"
+
 	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"flow/ecs"
+	"flow/events"
 	"flow/navigation"
 )
 
+// Building is a placed instance of a BuildingDef
+type Building struct {
+	ID       int
+	Def      *BuildingDef
+	Origin   navigation.Position
+	Rotation int                   // Clockwise quarter turns applied to the footprint
+	Cells    []navigation.Position // Grid cells covered by the building
+	TurretID ecs.Entity            // The building's turret entity, 0 if it has none
+}
+
 type BuildingSystem struct {
-	turretSystem *TurretSystem
-	navigator    *navigation.FlowFieldNavigator
-	config       Config
+	world     *ecs.World
+	navigator *navigation.FlowFieldNavigator
+	config    Config
+
+	buildings []*Building
+	occupied  map[navigation.Position]int // Building ID covering each cell
+	nextID    int
+	gold      int
+	bus       *events.Bus
 }
 
-func NewBuildingSystem(nav *navigation.FlowFieldNavigator, turretSys *TurretSystem, cfg Config) *BuildingSystem {
-	return &BuildingSystem{
-		turretSystem: turretSys,
-		navigator:    nav,
-		config:       cfg,
+func NewBuildingSystem(world *ecs.World, nav *navigation.FlowFieldNavigator, bus *events.Bus, cfg Config) *BuildingSystem {
+	bs := &BuildingSystem{
+		world:     world,
+		navigator: nav,
+		config:    cfg,
+		occupied:  make(map[navigation.Position]int),
+		nextID:    1,
+		gold:      cfg.StartingGold,
+		bus:       bus,
 	}
+
+	// Kills pay out the enemy's bounty
+	events.Subscribe(bus, func(event EnemyKilled) {
+		bs.AddGold(event.Enemy.Type.Bounty)
+	})
+
+	return bs
+}
+
+// Gold returns the gold available for building and upgrading
+func (bs *BuildingSystem) Gold() int {
+	return bs.gold
 }
 
+// AddGold credits gold, e.g. from enemy bounties
+func (bs *BuildingSystem) AddGold(amount int) {
+	bs.gold += amount
+}
+
+// PlaceBuilding places a basic turret at the given grid cell
 func (bs *BuildingSystem) PlaceBuilding(gridX, gridY int) bool {
-	pos := navigation.Position{X: gridX, Y: gridY}
-	
-	if !bs.navigator.GetGrid().IsValidPosition(pos) {
-		return false
-	}
-	
-	if !bs.navigator.GetGrid().IsPassable(pos) {
-		return false
-	}
-	
-	// Check if turret already exists at this position
-	for _, turret := range bs.turretSystem.Turrets {
-		if turret.PositionX == gridX && turret.PositionY == gridY {
-			return false
+	_, ok := bs.Place(TurretBuilding(), gridX, gridY, 0)
+	return ok
+}
+
+// Place places a building with its footprint rotated and anchored at the given cell.
+// Every covered cell must be inside the grid, passable, unoccupied and not the
+// goal, and the building's cost must be affordable. On wrapping grids covered
+// cells are wrapped onto the grid first. It returns the new building's ID and
+// whether placement succeeded.
+func (bs *BuildingSystem) Place(def *BuildingDef, gridX, gridY, rotation int) (int, bool) {
+	origin := navigation.Position{X: gridX, Y: gridY}
+	cells := def.Footprint.Rotated(rotation).Cells(origin)
+	if len(cells) == 0 || def.Cost > bs.gold {
+		return 0, false
+	}
+
+	view := bs.navigator.View()
+	for i, cell := range cells {
+		if !view.IsValidPosition(cell) || !view.IsPassable(cell) || view.CellTypeAt(cell) == navigation.Goal {
+			return 0, false
+		}
+
+		// Footprints hanging over a wrapping edge cover cells on the far side,
+		// which must not be covered twice
+		cell = view.Wrap(cell)
+		if slices.Contains(cells[:i], cell) {
+			return 0, false
+		}
+		if _, taken := bs.occupied[cell]; taken {
+			return 0, false
 		}
+		cells[i] = cell
 	}
-	
-	// Create turret
-	turret := Turret{
-		PositionX:   gridX,
-		PositionY:   gridY,
-		AttackRange: 3,
-		AttackSpeed: 1.0,
+
+	building := &Building{
+		ID:       bs.nextID,
+		Def:      def,
+		Origin:   cells[0],
+		Rotation: rotation,
+		Cells:    cells,
+	}
+	bs.nextID++
+	bs.gold -= def.Cost
+
+	bs.buildings = append(bs.buildings, building)
+	for _, cell := range cells {
+		bs.occupied[cell] = building.ID
+	}
+
+	// Turrets sit on the first cell of the footprint
+	if def.Turret != nil {
+		turret := *def.Turret
+		turret.PositionX = building.Origin.X
+		turret.PositionY = building.Origin.Y
+		building.TurretID = SpawnTurret(bs.world, turret)
 	}
-	bs.turretSystem.Turrets = append(bs.turretSystem.Turrets, turret)
-	
+
+	bs.applyAuras()
 	bs.updateNavigationCosts()
-	
-	return true
+	events.Publish(bs.bus, BuildingPlaced{Building: building})
+
+	return building.ID, true
+}
+
+// RemoveBuilding removes a building's whole footprint and its turret
+func (bs *BuildingSystem) RemoveBuilding(id int) bool {
+	for i, building := range bs.buildings {
+		if building.ID != id {
+			continue
+		}
+
+		bs.buildings = append(bs.buildings[:i], bs.buildings[i+1:]...)
+		for _, cell := range building.Cells {
+			delete(bs.occupied, cell)
+		}
+
+		if building.TurretID != 0 {
+			bs.world.Despawn(building.TurretID)
+		}
+
+		bs.applyAuras()
+		bs.updateNavigationCosts()
+		events.Publish(bs.bus, BuildingRemoved{Building: building})
+		return true
+	}
+
+	return false
 }
 
+// Upgrade raises a turret to its next level, paying that level's cost
+func (bs *BuildingSystem) Upgrade(turretID ecs.Entity) error {
+	turret, ok := ecs.Get[Turret](bs.world, turretID)
+	if !ok {
+		return ErrUnknownTurret
+	}
+
+	var def *BuildingDef
+	for _, building := range bs.buildings {
+		if building.TurretID == turretID {
+			def = building.Def
+			break
+		}
+	}
+	if def == nil {
+		return ErrUnknownTurret
+	}
+
+	// Level 1 is the base turret, so level n upgrades to Upgrades[n-1]
+	if turret.Level > len(def.Upgrades) {
+		return ErrMaxLevel
+	}
+	next := def.Upgrades[turret.Level-1]
+	if next.Cost > bs.gold {
+		return ErrInsufficientGold
+	}
+
+	bs.gold -= next.Cost
+	turret.applyLevel(next)
+
+	// Range and DPS changed, so the danger field must follow
+	bs.updateNavigationCosts()
+
+	return nil
+}
+
+// BuildingAt returns the building covering a grid cell
+func (bs *BuildingSystem) BuildingAt(gridX, gridY int) (*Building, bool) {
+	id, ok := bs.occupied[bs.navigator.View().Wrap(navigation.Position{X: gridX, Y: gridY})]
+	if !ok {
+		return nil, false
+	}
+
+	for _, building := range bs.buildings {
+		if building.ID == id {
+			return building, true
+		}
+	}
+
+	return nil, false
+}
+
+// Buildings returns all placed buildings in placement order
+func (bs *BuildingSystem) Buildings() []*Building {
+	return bs.buildings
+}
+
+// applyAuras recomputes the buff each turret receives from support buildings.
+// Auras don't stack: each stat takes the strongest bonus in range.
+func (bs *BuildingSystem) applyAuras() {
+	ecs.Each(bs.world, func(_ ecs.Entity, turret *Turret) {
+		buff := TurretBuff{AttackSpeedMultiplier: 1, DamageMultiplier: 1}
+
+		for _, building := range bs.buildings {
+			aura := building.Def.Aura
+			if building.Def.Kind != KindSupport || aura == nil || !building.reaches(turret.PositionX, turret.PositionY, aura.Radius) {
+				continue
+			}
+
+			buff.RangeBonus = max(buff.RangeBonus, aura.RangeBonus)
+			buff.AttackSpeedMultiplier = max(buff.AttackSpeedMultiplier, aura.AttackSpeedMultiplier)
+			buff.DamageMultiplier = max(buff.DamageMultiplier, aura.DamageMultiplier)
+		}
+
+		turret.Buff = buff
+	})
+}
+
+// reaches reports whether a grid cell lies within radius of any cell of the building
+func (b *Building) reaches(gridX, gridY, radius int) bool {
+	for _, cell := range b.Cells {
+		dx := float64(cell.X - gridX)
+		dy := float64(cell.Y - gridY)
+		if math.Sqrt(dx*dx+dy*dy) <= float64(radius) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateNavigationCosts writes building cells and turret danger into the navigator
+// with a single flow field recompute
 func (bs *BuildingSystem) updateNavigationCosts() {
-	grid := bs.navigator.GetGrid()
-	costs := make([][]int, grid.Height)
-	
-	for y := range grid.Height {
-		costs[y] = make([]int, grid.Width)
-		copy(costs[y], grid.Costs[y])
-	}
-	
-	for _, turret := range bs.turretSystem.Turrets {
-		if turret.PositionX < grid.Width && turret.PositionY < grid.Height {
-			costs[turret.PositionY][turret.PositionX] = -1
-			grid.SetBuilding(navigation.Position{X: turret.PositionX, Y: turret.PositionY})
+	movements := []navigation.MovementLayer{navigation.Ground, navigation.Air}
+	layers := []string{navigation.LayerBuildings}
+	danger := make([][][]int, len(movements))
+	for i, movement := range movements {
+		layers = append(layers, navigation.DangerLayer(movement))
+		danger[i] = DangerCosts(bs.world, bs.config, movement)
+	}
+
+	err := bs.navigator.UpdateLayers(layers, func(layers []*navigation.CostLayer) error {
+		buildings := layers[0]
+		buildings.Clear()
+		for cell := range bs.occupied {
+			if err := buildings.Set(cell, -1); err != nil {
+				return err
+			}
 		}
+
+		// Each movement layer only fears the turrets that can hit it
+		for i, costs := range danger {
+			for y := range costs {
+				copy(layers[i+1].Costs[y], costs[y])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to update building costs: %v", err)
 	}
-	
-	bs.navigator.UpdateCosts(costs)
 }
 
+// Draw renders every building according to its kind
 func (bs *BuildingSystem) Draw() {
-	for _, turret := range bs.turretSystem.Turrets {
-		cellX := int32(bs.config.MarginX + turret.PositionX*bs.config.CellSize)
-		cellY := int32(bs.config.MarginY + turret.PositionY*bs.config.CellSize)
-		
-		rl.DrawRectangle(cellX, cellY, int32(bs.config.CellSize), int32(bs.config.CellSize), rl.Blue)
-		
-		rl.DrawRectangleLines(cellX, cellY, int32(bs.config.CellSize), int32(bs.config.CellSize), rl.DarkBlue)
+	for _, building := range bs.buildings {
+		switch building.Def.Kind {
+		case KindWall:
+			bs.drawWall(building)
+		case KindTurret:
+			bs.drawTurret(building)
+		case KindSupport:
+			bs.drawSupport(building)
+		}
+	}
+}
+
+// drawWall renders a wall as solid blocks
+func (bs *BuildingSystem) drawWall(building *Building) {
+	cellSize := int32(bs.config.CellSize)
+	for _, cell := range building.Cells {
+		cellX, cellY := bs.cellOrigin(cell)
+		rl.DrawRectangle(cellX, cellY, cellSize, cellSize, building.Def.Color)
+		rl.DrawRectangleLines(cellX, cellY, cellSize, cellSize, rl.Black)
+	}
+}
+
+// drawTurret renders a turret as a pale base with a gun head on its origin cell
+func (bs *BuildingSystem) drawTurret(building *Building) {
+	cellSize := int32(bs.config.CellSize)
+	for _, cell := range building.Cells {
+		cellX, cellY := bs.cellOrigin(cell)
+		rl.DrawRectangle(cellX, cellY, cellSize, cellSize, rl.Fade(building.Def.Color, 0.35))
+		rl.DrawRectangleLines(cellX, cellY, cellSize, cellSize, rl.DarkBlue)
+	}
+
+	originX, originY := bs.cellOrigin(building.Origin)
+	centerX := originX + cellSize/2
+	centerY := originY + cellSize/2
+	rl.DrawCircle(centerX, centerY, float32(cellSize)/3, building.Def.Color)
+	rl.DrawCircleLines(centerX, centerY, float32(cellSize)/3, rl.DarkBlue)
+
+	// One pip per upgrade level along the bottom of the origin cell
+	if turret, ok := ecs.Get[Turret](bs.world, building.TurretID); ok {
+		for level := range turret.Level {
+			rl.DrawCircle(originX+6+int32(level)*8, originY+cellSize-6, 2.5, rl.Gold)
+		}
 	}
 }
 
-func (bs *BuildingSystem) GetTurretSystem() *TurretSystem {
-	return bs.turretSystem
-}
\ No newline at end of file
+// drawSupport renders a support building with its aura radius
+func (bs *BuildingSystem) drawSupport(building *Building) {
+	cellSize := int32(bs.config.CellSize)
+	for _, cell := range building.Cells {
+		cellX, cellY := bs.cellOrigin(cell)
+		rl.DrawRectangle(cellX, cellY, cellSize, cellSize, rl.Fade(building.Def.Color, 0.5))
+		rl.DrawRectangleLines(cellX, cellY, cellSize, cellSize, building.Def.Color)
+	}
+
+	originX, originY := bs.cellOrigin(building.Origin)
+	center := rl.Vector2{X: float32(originX + cellSize/2), Y: float32(originY + cellSize/2)}
+	rl.DrawPoly(center, 4, float32(cellSize)/3, 0, building.Def.Color)
+	if aura := building.Def.Aura; aura != nil {
+		radius := float32(aura.Radius*bs.config.CellSize) + float32(cellSize)/2
+		rl.DrawCircleLines(int32(center.X), int32(center.Y), radius, rl.Fade(building.Def.Color, 0.6))
+	}
+}
+
+// cellOrigin returns the top-left pixel of a grid cell
+func (bs *BuildingSystem) cellOrigin(cell navigation.Position) (int32, int32) {
+	return int32(bs.config.MarginX + cell.X*bs.config.CellSize), int32(bs.config.MarginY + cell.Y*bs.config.CellSize)
+}
@@ -0,0 +1,74 @@
+package systems
+
+import (
+	"testing"
+
+	"flow/ecs"
+	"flow/events"
+	"flow/navigation"
+)
+
+func TestPlaceAcrossWrappingEdges(t *testing.T) {
+	config := DefaultConfig()
+	navConfig := navigation.EightWayConfig(config.Width, config.Height)
+	navConfig.WrapX, navConfig.WrapY = true, true
+	navigator, err := navigation.NewFlowFieldNavigator(navConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer navigator.Close()
+
+	buildings := NewBuildingSystem(ecs.NewWorld(), navigator, events.NewBus(), config)
+	wall := WallBuilding()
+	last := config.Width - 1
+	id, ok := buildings.Place(wall, last, last, 0)
+	if !ok {
+		t.Fatal("wall over the corner was rejected")
+	}
+	if gold := config.StartingGold - wall.Cost; buildings.Gold() != gold {
+		t.Errorf("gold = %d, want %d", buildings.Gold(), gold)
+	}
+
+	// The footprint covers one cell in each corner of the grid
+	view := navigator.View()
+	for _, cell := range []navigation.Position{{X: last, Y: last}, {X: 0, Y: last}, {X: last, Y: 0}, {X: 0, Y: 0}} {
+		if view.CellTypeAt(cell) != navigation.Building {
+			t.Errorf("cell %v is not a building", cell)
+		}
+		if building, ok := buildings.BuildingAt(cell.X, cell.Y); !ok || building.ID != id {
+			t.Errorf("no building found at %v", cell)
+		}
+	}
+
+	// Cells covered through the wrap are taken like any other
+	if _, ok := buildings.Place(WallBuilding(), -1, -1, 0); ok {
+		t.Error("wall overlapping the wrapped footprint was placed")
+	}
+}
+
+func TestPlaceRejectsGoal(t *testing.T) {
+	config := DefaultConfig()
+	navigator, err := navigation.NewFlowFieldNavigator(navigation.EightWayConfig(config.Width, config.Height))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer navigator.Close()
+
+	goal := navigation.Position{X: config.Width - 1, Y: config.Height - 1}
+	if err := navigator.SetGoal(goal); err != nil {
+		t.Fatal(err)
+	}
+
+	buildings := NewBuildingSystem(ecs.NewWorld(), navigator, events.NewBus(), config)
+	if _, ok := buildings.Place(TurretBuilding(), goal.X, goal.Y, 0); ok {
+		t.Error("turret was placed on the goal")
+	}
+
+	// A wider footprint that only reaches the goal is rejected too
+	if _, ok := buildings.Place(WallBuilding(), goal.X-1, goal.Y-1, 0); ok {
+		t.Error("wall covering the goal was placed")
+	}
+	if buildings.Gold() != config.StartingGold {
+		t.Errorf("gold = %d after rejected placements, want %d", buildings.Gold(), config.StartingGold)
+	}
+}
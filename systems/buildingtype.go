@@ -0,0 +1,228 @@
+package systems
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// BuildingKind groups buildings by how they behave once placed
+type BuildingKind int
+
+const (
+	KindWall    BuildingKind = iota // Blocks movement and does nothing else
+	KindTurret                      // Attacks enemies in range
+	KindSupport                     // Buffs nearby turrets with an aura
+)
+
+// String returns the lowercase name of the building kind
+func (k BuildingKind) String() string {
+	switch k {
+	case KindWall:
+		return "wall"
+	case KindTurret:
+		return "turret"
+	case KindSupport:
+		return "support"
+	default:
+		return fmt.Sprintf("BuildingKind(%d)", int(k))
+	}
+}
+
+// MarshalText encodes the building kind by name
+func (k BuildingKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes a building kind from its name
+func (k *BuildingKind) UnmarshalText(text []byte) error {
+	for kind := KindWall; kind <= KindSupport; kind++ {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown building kind %q", text)
+}
+
+// Aura describes the buff a support building grants to turrets within its radius
+type Aura struct {
+	Radius                int     `json:"radius"`                // Range in cells from any covered cell
+	RangeBonus            int     `json:"rangeBonus"`            // Extra attack range in cells
+	AttackSpeedMultiplier float64 `json:"attackSpeedMultiplier"` // Multiplier on attacks per second
+	DamageMultiplier      float64 `json:"damageMultiplier"`      // Multiplier on damage per hit
+}
+
+// BuildingDef describes a kind of building and the cells it covers
+type BuildingDef struct {
+	Name      string        `json:"name"`
+	Kind      BuildingKind  `json:"kind"`
+	Cost      int           `json:"cost"`      // Gold needed to place the building
+	Footprint Footprint     `json:"footprint"` // Defaults to a single cell
+	Color     rl.Color      `json:"color"`
+	Turret    *Turret       `json:"turret"`   // Turret template placed at the origin cell, for turret kinds
+	Upgrades  []TurretLevel `json:"upgrades"` // Stats for levels 2 and up, in order
+	Aura      *Aura         `json:"aura"`     // Buff granted to nearby turrets, for support kinds
+}
+
+// validate checks that the definition has the data its kind needs
+func (d BuildingDef) validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("%w: missing name", ErrInvalidBuildingDef)
+	}
+	if d.Cost < 0 {
+		return fmt.Errorf("%w: %q has a negative cost", ErrInvalidBuildingDef, d.Name)
+	}
+
+	switch d.Kind {
+	case KindTurret:
+		if d.Turret == nil || d.Turret.AttackRange <= 0 || d.Turret.AttackSpeed <= 0 {
+			return fmt.Errorf("%w: %q needs a turret with positive range and speed", ErrInvalidBuildingDef, d.Name)
+		}
+		for i, level := range d.Upgrades {
+			if level.AttackRange <= 0 || level.AttackSpeed <= 0 || level.Cost < 0 {
+				return fmt.Errorf("%w: %q upgrade %d needs positive range and speed", ErrInvalidBuildingDef, d.Name, i+2)
+			}
+		}
+	case KindSupport:
+		if d.Aura == nil || d.Aura.Radius <= 0 {
+			return fmt.Errorf("%w: %q needs an aura with positive radius", ErrInvalidBuildingDef, d.Name)
+		}
+	}
+
+	return nil
+}
+
+// TurretBuilding returns the definition of a single-cell basic turret
+func TurretBuilding() *BuildingDef {
+	turret := BasicTurret(0, 0)
+	return &BuildingDef{
+		Name:      "turret",
+		Kind:      KindTurret,
+		Cost:      10,
+		Footprint: SingleCell,
+		Color:     rl.Blue,
+		Turret:    &turret,
+		Upgrades: []TurretLevel{
+			{AttackRange: 3, AttackSpeed: 1.5, Damage: 1.5, Cost: 15},
+			{AttackRange: 4, AttackSpeed: 2.0, Damage: 2.0, Cost: 25},
+		},
+	}
+}
+
+// SlowTowerBuilding returns the definition of a single-cell slow tower
+func SlowTowerBuilding() *BuildingDef {
+	turret := SlowTower(0, 0)
+	return &BuildingDef{
+		Name:      "slow tower",
+		Kind:      KindTurret,
+		Cost:      15,
+		Footprint: SingleCell,
+		Color:     rl.SkyBlue,
+		Turret:    &turret,
+		Upgrades: []TurretLevel{
+			{AttackRange: 3, AttackSpeed: 2.5, Cost: 20},
+		},
+	}
+}
+
+// WallBuilding returns the definition of a 2x2 wall block
+func WallBuilding() *BuildingDef {
+	return &BuildingDef{Name: "wall", Kind: KindWall, Cost: 4, Footprint: Square2x2, Color: rl.DarkGray}
+}
+
+// LWallBuilding returns the definition of an L-shaped wall
+func LWallBuilding() *BuildingDef {
+	return &BuildingDef{Name: "l-wall", Kind: KindWall, Cost: 4, Footprint: LShape, Color: rl.DarkGray}
+}
+
+// BuildingRegistry holds the building definitions available for placement
+type BuildingRegistry struct {
+	defs  map[string]*BuildingDef
+	names []string // Registration order
+}
+
+// NewBuildingRegistry creates an empty building registry
+func NewBuildingRegistry() *BuildingRegistry {
+	return &BuildingRegistry{
+		defs: make(map[string]*BuildingDef),
+	}
+}
+
+// DefaultBuildingRegistry returns a registry holding the built-in building definitions
+func DefaultBuildingRegistry() *BuildingRegistry {
+	registry := NewBuildingRegistry()
+	for _, def := range []*BuildingDef{TurretBuilding(), SlowTowerBuilding(), WallBuilding(), LWallBuilding()} {
+		// Built-in definitions are valid and uniquely named
+		_ = registry.Register(*def)
+	}
+	return registry
+}
+
+// Register adds a building definition, defaulting to a single-cell footprint and
+// turrets that hit both ground and air
+func (r *BuildingRegistry) Register(def BuildingDef) error {
+	if err := def.validate(); err != nil {
+		return err
+	}
+	if _, ok := r.defs[def.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrBuildingDefExists, def.Name)
+	}
+
+	if len(def.Footprint) == 0 {
+		def.Footprint = SingleCell
+	}
+	if def.Turret != nil && def.Turret.Targets == 0 {
+		turret := *def.Turret
+		turret.Targets = TargetBoth
+		def.Turret = &turret
+	}
+
+	r.defs[def.Name] = &def
+	r.names = append(r.names, def.Name)
+	return nil
+}
+
+// Get returns the building definition registered under the given name
+func (r *BuildingRegistry) Get(name string) (*BuildingDef, error) {
+	def, ok := r.defs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBuildingDef, name)
+	}
+	return def, nil
+}
+
+// Names returns the registered building names in registration order
+func (r *BuildingRegistry) Names() []string {
+	return r.names
+}
+
+// Load registers every building definition from a JSON array
+func (r *BuildingRegistry) Load(reader io.Reader) error {
+	var defs []BuildingDef
+	if err := json.NewDecoder(reader).Decode(&defs); err != nil {
+		return fmt.Errorf("decode building definitions: %w", err)
+	}
+
+	for _, def := range defs {
+		if err := r.Register(def); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadFile registers every building definition from a JSON data file
+func (r *BuildingRegistry) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return r.Load(file)
+}
@@ -0,0 +1,30 @@
+package systems
+
+import "flow/ecs"
+
+// Clock is the shared simulation clock, stored as a world resource so every
+// system reads the same time
+type Clock struct {
+	Now  float64 // Simulation time in seconds
+	Step float32 // Seconds advanced by each tick
+}
+
+// NewClock creates a clock and registers it as the world's clock resource
+func NewClock(world *ecs.World, step float32) *Clock {
+	clock := &Clock{Step: step}
+	ecs.SetResource(world, clock)
+	return clock
+}
+
+// Tick advances the clock by one step
+func (c *Clock) Tick() {
+	c.Now += float64(c.Step)
+}
+
+// clockOf returns the world's clock, or a stopped clock if none is registered
+func clockOf(world *ecs.World) *Clock {
+	if clock, ok := ecs.Resource[*Clock](world); ok {
+		return clock
+	}
+	return &Clock{}
+}
@@ -1,28 +1,66 @@
 package systems
 
 import (
+	"fmt"
 	"math"
+	"runtime"
+	"sync"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 
+	"flow/ecs"
+	"flow/events"
 	"flow/navigation"
 )
 
+// minChunkSize is the fewest enemies handed to one update goroutine, so small
+// crowds don't pay for goroutines they can't use
+const minChunkSize = 16
+
+// maxSpawnAttempts is how many random cells are tried for a spawn that can
+// reach the goal before giving up on one
+const maxSpawnAttempts = 10
+
 // Enemy represents an animated agent that follows the flow field
 type Enemy struct {
 	Position  rl.Vector2 // Current position in pixels
 	Velocity  rl.Vector2 // Current velocity for smooth movement
 	GridPos   rl.Vector2 // Current grid cell position (as floats for easier conversion)
+	Level     int        // Grid level the enemy is on; steering only reacts to enemies on the same level
 	TargetPos rl.Vector2 // Target position for smooth movement
 	Moving    bool       // Whether the unit is currently moving
 	Radius    float32    // Unit collision radius
+	Health    float32    // Remaining hit points
+	Type      *EnemyType // Shared stats and behaviour for this kind of enemy
+
+	Effects []StatusEffect // Active status effects
+}
+
+// Cell returns the grid cell the enemy is in, including its level
+func (e *Enemy) Cell() navigation.Position {
+	return navigation.Position{X: int(e.GridPos.X), Y: int(e.GridPos.Y), Level: e.Level}
+}
+
+// TakeDamage applies damage reduced by the enemy's armor and reports whether it died
+func (e *Enemy) TakeDamage(amount float32) bool {
+	dealt := max(amount-e.Type.Armor, amount*minDamageFraction)
+	e.Health -= dealt
+	return e.IsDead()
+}
+
+// IsDead reports whether the enemy has run out of health
+func (e *Enemy) IsDead() bool {
+	return e.Health <= 0
 }
 
 // EnemySystem manages all enemy units and their behaviors
 type EnemySystem struct {
-	enemies   []*Enemy
-	navigator *navigation.FlowFieldNavigator
-	config    Config
+	world       *ecs.World
+	navigator   *navigation.FlowFieldNavigator
+	config      Config
+	defaultType *EnemyType
+	goal        navigation.Position // Latest goal, kept in sync through GoalChanged
+	bus         *events.Bus
 }
 
 // Config holds the configuration for enemy behaviors
@@ -33,8 +71,19 @@ type Config struct {
 	MarginX       int
 	MarginY       int
 
+	// Simulation parameters
+	TimeStep        float32 // Simulated seconds advanced by each update
+	Workers         int     // Goroutines for the enemy update, 0 uses GOMAXPROCS
+	ProjectileSpeed float32 // Projectile speed in pixels per update
+
 	// Movement parameters
-	UnitSpeed float32
+	UnitSpeed float32 // Speed of the default enemy type
+
+	// Economy parameters
+	StartingGold int // Gold available to the player at the start
+
+	// Danger avoidance parameters
+	DangerScale float32 // Extra path cost per point of turret DPS covering a cell
 
 	// Steering behavior parameters
 	SeparationRadius float32
@@ -55,8 +104,15 @@ func DefaultConfig() Config {
 		MarginX:  30,
 		MarginY:  30,
 
+		TimeStep:        1.0 / 60.0,
+		ProjectileSpeed: 8.0,
+
 		UnitSpeed: 2.0,
 
+		StartingGold: 50,
+
+		DangerScale: 2.0,
+
 		SeparationRadius: 15.0,
 		SeparationForce:  2.0,
 		AlignmentRadius:  25.0,
@@ -68,26 +124,44 @@ func DefaultConfig() Config {
 }
 
 // NewEnemySystem creates a new enemy management system
-func NewEnemySystem(navigator *navigation.FlowFieldNavigator, config Config) *EnemySystem {
-	return &EnemySystem{
-		enemies:   make([]*Enemy, 0),
-		navigator: navigator,
-		config:    config,
+func NewEnemySystem(world *ecs.World, navigator *navigation.FlowFieldNavigator, bus *events.Bus, config Config) *EnemySystem {
+	defaultType := DefaultEnemyType(config)
+	es := &EnemySystem{
+		world:       world,
+		navigator:   navigator,
+		config:      config,
+		defaultType: &defaultType,
+		goal:        navigator.GetGoal(),
+		bus:         bus,
 	}
+
+	events.Subscribe(bus, func(event navigation.GoalChanged) {
+		es.goal = event.Goal
+	})
+
+	return es
 }
 
-// SpawnEnemies creates the specified number of enemies
+// SpawnEnemies creates the specified number of enemies of the default type
 func (es *EnemySystem) SpawnEnemies(count int) {
+	es.SpawnEnemiesOfType(es.defaultType, count)
+}
+
+// SpawnEnemiesOfType creates the specified number of enemies of the given type
+func (es *EnemySystem) SpawnEnemiesOfType(enemyType *EnemyType, count int) {
+	es.registerProfile(enemyType)
+
 	for range count {
 		// Spread units across the bottom area
-		startX := float32(rl.GetRandomValue(0, int32(es.config.Width-1)))
-		startY := float32(rl.GetRandomValue(int32(es.config.Height-3), int32(es.config.Height-1)))
+		startX, startY := es.spawnCell()
 
-		enemy := &Enemy{
+		enemy := Enemy{
 			GridPos:  rl.Vector2{X: startX, Y: startY},
 			Velocity: rl.Vector2{X: 0, Y: 0},
 			Moving:   false,
-			Radius:   4.0,
+			Radius:   enemyType.Radius,
+			Health:   enemyType.Health,
+			Type:     enemyType,
 		}
 
 		// Set initial pixel position with small random offset
@@ -113,122 +187,379 @@ func (es *EnemySystem) SpawnEnemies(count int) {
 		}
 		enemy.TargetPos = enemy.Position
 
-		es.enemies = append(es.enemies, enemy)
+		entity := es.world.Spawn()
+		ecs.Add(es.world, entity, enemy)
+		events.Publish(es.bus, EnemySpawned{Entity: entity, Enemy: enemy})
 	}
 }
 
-// Update updates all enemies with steering behaviors
+// Update removes dead enemies and advances the rest with steering behaviors.
+// Steering is computed in parallel from a snapshot of the previous tick and
+// committed afterwards, so the result doesn't depend on update order.
 func (es *EnemySystem) Update() {
-	for _, enemy := range es.enemies {
-		// Calculate steering forces
-		separation := es.calculateSeparation(enemy)
-		alignment := es.calculateAlignment(enemy)
-		cohesion := es.calculateCohesion(enemy)
-		obstacleAvoid := es.calculateObstacleAvoidance(enemy)
-
-		// Get flow field direction
-		flowForce := es.calculateFlowForce(enemy)
-
-		// Combine all forces (flow field has MUCH higher weight for pathfinding)
-		totalForce := rl.Vector2{
-			X: flowForce.X*5.0 + separation.X*0.5 + alignment.X*0.2 + cohesion.X*0.1 + obstacleAvoid.X*10.0,
-			Y: flowForce.Y*5.0 + separation.Y*0.5 + alignment.Y*0.2 + cohesion.Y*0.1 + obstacleAvoid.Y*10.0,
+	es.removeDead()
+
+	clock := clockOf(es.world)
+	var entities []ecs.Entity
+	var enemies []*Enemy
+	ecs.Each(es.world, func(entity ecs.Entity, enemy *Enemy) {
+		enemy.updateEffects(clock.Now, clock.Step)
+		entities = append(entities, entity)
+		enemies = append(enemies, enemy)
+	})
+
+	// Every enemy steers from the same read-only snapshot of enemies and
+	// fields, so a field published mid-tick waits for the next tick
+	view := es.navigator.View()
+	previous := make([]Enemy, len(enemies))
+	for i, enemy := range enemies {
+		previous[i] = *enemy
+	}
+
+	next := make([]Enemy, len(previous))
+	es.parallel(len(previous), func(start, end int) {
+		for i := start; i < end; i++ {
+			next[i] = es.step(view, &previous[i], previous)
 		}
+	})
+
+	// Commit the new state, respawning enemies that reached the goal and
+	// moving enemies through portals
+	var leaked []EnemyLeaked
+	var teleported []EnemyTeleported
+	for i, enemy := range enemies {
+		enemy.Position = next[i].Position
+		enemy.Velocity = next[i].Velocity
+		enemy.GridPos = next[i].GridPos
+
+		if enemy.Cell() == es.goal {
+			leaked = append(leaked, EnemyLeaked{Entity: entities[i], Enemy: *enemy})
+			es.respawn(enemy)
+		} else if portal, ok := es.portal(view, enemy); ok {
+			es.teleport(enemy, portal.To)
+			teleported = append(teleported, EnemyTeleported{Entity: entities[i], Enemy: *enemy, Portal: portal})
+		}
+	}
 
-		// Apply force to velocity
-		enemy.Velocity.X += totalForce.X * es.config.MaxSteerForce
-		enemy.Velocity.Y += totalForce.Y * es.config.MaxSteerForce
+	// Publish once the commit is done, since subscribers may spawn or despawn
+	for _, event := range leaked {
+		events.Publish(es.bus, event)
+	}
+	for _, event := range teleported {
+		events.Publish(es.bus, event)
+	}
+}
 
-		// Limit velocity to max speed
-		speed := rl.Vector2Length(enemy.Velocity)
-		if speed > es.config.UnitSpeed {
-			enemy.Velocity.X = (enemy.Velocity.X / speed) * es.config.UnitSpeed
-			enemy.Velocity.Y = (enemy.Velocity.Y / speed) * es.config.UnitSpeed
-		}
+// step returns the enemy's state after one tick of steering, reading neighbours
+// only from the previous tick's snapshot and fields only from the tick's view
+func (es *EnemySystem) step(view navigation.GridView, enemy *Enemy, neighbours []Enemy) Enemy {
+	next := *enemy
+
+	// Stunned enemies hold position until the stun wears off
+	if enemy.IsStunned() {
+		next.Velocity = rl.Vector2{X: 0, Y: 0}
+		return next
+	}
+
+	// Calculate steering forces
+	separation := es.calculateSeparation(enemy, neighbours)
+	alignment := es.calculateAlignment(enemy, neighbours)
+	cohesion := es.calculateCohesion(enemy, neighbours)
 
-		// Update position
-		enemy.Position.X += enemy.Velocity.X
-		enemy.Position.Y += enemy.Velocity.Y
+	// Flying enemies pass over obstacles so they don't avoid them
+	obstacleAvoid := rl.Vector2{X: 0, Y: 0}
+	if enemy.Type.Movement != navigation.Air {
+		obstacleAvoid = es.calculateObstacleAvoidance(view, enemy)
+	}
+
+	// Get flow field direction
+	flowForce := es.calculateFlowForce(view, enemy)
+
+	// Combine all forces using the enemy type's steering weights
+	weights := enemy.Type.Steering
+	totalForce := rl.Vector2{
+		X: flowForce.X*weights.Flow + separation.X*weights.Separation + alignment.X*weights.Alignment +
+			cohesion.X*weights.Cohesion + obstacleAvoid.X*weights.Avoidance,
+		Y: flowForce.Y*weights.Flow + separation.Y*weights.Separation + alignment.Y*weights.Alignment +
+			cohesion.Y*weights.Cohesion + obstacleAvoid.Y*weights.Avoidance,
+	}
+
+	// Apply force to the next state's velocity
+	enemy = &next
+	enemy.Velocity.X += totalForce.X * es.config.MaxSteerForce
+	enemy.Velocity.Y += totalForce.Y * es.config.MaxSteerForce
+
+	// Limit velocity to the enemy type's max speed after slows
+	maxSpeed := enemy.Type.Speed * enemy.SpeedMultiplier()
+	speed := rl.Vector2Length(enemy.Velocity)
+	if speed > maxSpeed {
+		enemy.Velocity.X = (enemy.Velocity.X / speed) * maxSpeed
+		enemy.Velocity.Y = (enemy.Velocity.Y / speed) * maxSpeed
+	}
+
+	// Update position, carrying enemies across wrapping edges
+	enemy.Position.X += enemy.Velocity.X
+	enemy.Position.Y += enemy.Velocity.Y
+	enemy.Position = es.wrap(enemy.Position)
+
+	// Update grid position
+	enemy.GridPos.X = (enemy.Position.X - float32(es.config.MarginX) - float32(es.config.CellSize)/2) / float32(
+		es.config.CellSize,
+	)
+	enemy.GridPos.Y = (enemy.Position.Y - float32(es.config.MarginY) - float32(es.config.CellSize)/2) / float32(
+		es.config.CellSize,
+	)
 
-		// Update grid position
-		enemy.GridPos.X = (enemy.Position.X - float32(es.config.MarginX) - float32(es.config.CellSize)/2) / float32(
+	return next
+}
+
+// spawnCell picks a random cell in the bottom area, preferring one that can
+// reach the goal. After maxSpawnAttempts misses it settles for the last pick.
+func (es *EnemySystem) spawnCell() (x, y float32) {
+	for range maxSpawnAttempts {
+		cellX := int(rl.GetRandomValue(0, int32(es.config.Width-1)))
+		cellY := int(rl.GetRandomValue(int32(es.config.Height-3), int32(es.config.Height-1)))
+		x, y = float32(cellX), float32(cellY)
+		if es.navigator.IsReachable(navigation.Position{X: cellX, Y: cellY}, es.goal) {
+			break
+		}
+	}
+	return x, y
+}
+
+// respawn moves an enemy that reached the goal back to a random bottom position
+func (es *EnemySystem) respawn(enemy *Enemy) {
+	// Reset to random bottom position
+	startX, startY := es.spawnCell()
+	enemy.GridPos = rl.Vector2{X: startX, Y: startY}
+	enemy.Level = 0
+	enemy.Position = rl.Vector2{
+		X: float32(
+			es.config.MarginX,
+		) + startX*float32(
 			es.config.CellSize,
-		)
-		enemy.GridPos.Y = (enemy.Position.Y - float32(es.config.MarginY) - float32(es.config.CellSize)/2) / float32(
+		) + float32(
 			es.config.CellSize,
-		)
+		)/2,
+		Y: float32(
+			es.config.MarginY,
+		) + startY*float32(
+			es.config.CellSize,
+		) + float32(
+			es.config.CellSize,
+		)/2,
+	}
+	enemy.Velocity = rl.Vector2{X: 0, Y: 0}
+}
 
-		// Check if reached goal and reset
-		currentGoal := es.navigator.GetGoal()
-		if int(enemy.GridPos.X) == currentGoal.X && int(enemy.GridPos.Y) == currentGoal.Y {
-			// Reset to random bottom position
-			startX := float32(rl.GetRandomValue(0, int32(es.config.Width-1)))
-			startY := float32(
-				rl.GetRandomValue(int32(es.config.Height-3), int32(es.config.Height-1)),
-			)
-			enemy.GridPos = rl.Vector2{X: startX, Y: startY}
-			enemy.Position = rl.Vector2{
-				X: float32(
-					es.config.MarginX,
-				) + startX*float32(
-					es.config.CellSize,
-				) + float32(
-					es.config.CellSize,
-				)/2,
-				Y: float32(
-					es.config.MarginY,
-				) + startY*float32(
-					es.config.CellSize,
-				) + float32(
-					es.config.CellSize,
-				)/2,
-			}
-			enemy.Velocity = rl.Vector2{X: 0, Y: 0}
-		}
+// portal returns the portal an enemy stands on if its field routes it through.
+// Entrances the path doesn't use are walked over like any other cell.
+func (es *EnemySystem) portal(view navigation.GridView, enemy *Enemy) (navigation.Portal, bool) {
+	pos := enemy.Cell()
+	if flowDirection(view, enemy, pos) != navigation.UsePortal {
+		return navigation.Portal{}, false
 	}
+	return view.PortalAt(pos)
 }
 
-// Draw renders all enemies
+// teleport moves an enemy to the centre of a cell, which may be on another
+// level, keeping its velocity
+func (es *EnemySystem) teleport(enemy *Enemy, cell navigation.Position) {
+	enemy.GridPos = rl.Vector2{X: float32(cell.X), Y: float32(cell.Y)}
+	enemy.Level = cell.Level
+	enemy.Position = rl.Vector2{
+		X: float32(es.config.MarginX + cell.X*es.config.CellSize + es.config.CellSize/2),
+		Y: float32(es.config.MarginY + cell.Y*es.config.CellSize + es.config.CellSize/2),
+	}
+}
+
+// parallel splits n items into chunks and runs fn on each chunk on its own
+// goroutine, returning once every chunk is done
+func (es *EnemySystem) parallel(n int, fn func(start, end int)) {
+	workers := es.config.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chunk := max((n+workers-1)/workers, minChunkSize)
+
+	var wg sync.WaitGroup
+	for start := 0; start < n; start += chunk {
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			fn(start, end)
+		}(start, min(start+chunk, n))
+	}
+	wg.Wait()
+}
+
+// Draw renders all enemies. On wrapping grids enemies crossing an edge are
+// drawn on both sides, clipped to the grid.
 func (es *EnemySystem) Draw() {
-	for _, enemy := range es.enemies {
-		// Draw enemy as a red circle with black outline
-		rl.DrawCircle(int32(enemy.Position.X), int32(enemy.Position.Y), enemy.Radius, rl.Red)
-		rl.DrawCircleLines(int32(enemy.Position.X), int32(enemy.Position.Y), enemy.Radius, rl.Black)
-
-		// Draw velocity direction line
-		if rl.Vector2Length(enemy.Velocity) > 0.1 {
-			endX := enemy.Position.X + enemy.Velocity.X*5
-			endY := enemy.Position.Y + enemy.Velocity.Y*5
-			rl.DrawLine(
-				int32(enemy.Position.X),
-				int32(enemy.Position.Y),
-				int32(endX),
-				int32(endY),
-				rl.Black,
-			)
+	wrapX, wrapY := es.navigator.Wrapping()
+	if wrapX || wrapY {
+		rl.BeginScissorMode(int32(es.config.MarginX), int32(es.config.MarginY),
+			int32(es.config.Width*es.config.CellSize), int32(es.config.Height*es.config.CellSize))
+		defer rl.EndScissorMode()
+	}
+
+	ecs.Each(es.world, func(_ ecs.Entity, enemy *Enemy) {
+		for _, position := range es.drawPositions(enemy) {
+			drawEnemy(enemy, position)
 		}
+	})
+}
+
+// drawPositions returns where to draw an enemy: its position, plus copies on
+// the opposite side of any wrapping edge it overlaps
+func (es *EnemySystem) drawPositions(enemy *Enemy) []rl.Vector2 {
+	wrapX, wrapY := es.navigator.Wrapping()
+	reach := enemy.Radius * 2
+	xs := wrapCopies(enemy.Position.X, float32(es.config.MarginX), float32(es.config.Width*es.config.CellSize), reach, wrapX)
+	ys := wrapCopies(enemy.Position.Y, float32(es.config.MarginY), float32(es.config.Height*es.config.CellSize), reach, wrapY)
+
+	positions := make([]rl.Vector2, 0, len(xs)*len(ys))
+	for _, x := range xs {
+		for _, y := range ys {
+			positions = append(positions, rl.Vector2{X: x, Y: y})
+		}
+	}
+	return positions
+}
+
+// wrapCopies returns a coordinate plus its copy across the nearer edge of a
+// wrapping span if it lies within reach of that edge
+func wrapCopies(value, origin, span, reach float32, wrap bool) []float32 {
+	switch {
+	case !wrap:
+		return []float32{value}
+	case value-origin < reach:
+		return []float32{value, value + span}
+	case origin+span-value < reach:
+		return []float32{value, value - span}
+	default:
+		return []float32{value}
+	}
+}
+
+// drawEnemy renders one enemy at a position
+func drawEnemy(enemy *Enemy, position rl.Vector2) {
+	// Draw a shadow under flying enemies to lift them off the ground
+	if enemy.Type.Movement == navigation.Air {
+		rl.DrawEllipse(int32(position.X), int32(position.Y+enemy.Radius*1.5), enemy.Radius, enemy.Radius/2, rl.Fade(rl.Black, 0.3))
+	}
+
+	// Draw enemy as a circle in its type's colour with black outline
+	rl.DrawCircle(int32(position.X), int32(position.Y), enemy.Radius, enemy.Type.Color)
+	rl.DrawCircleLines(int32(position.X), int32(position.Y), enemy.Radius, rl.Black)
+
+	// Ring enemies that are under a status effect
+	if color, ok := statusColor(enemy); ok {
+		rl.DrawCircleLines(int32(position.X), int32(position.Y), enemy.Radius+2, color)
+	}
+
+	// Draw velocity direction line
+	if rl.Vector2Length(enemy.Velocity) > 0.1 {
+		endX := position.X + enemy.Velocity.X*5
+		endY := position.Y + enemy.Velocity.Y*5
+		rl.DrawLine(
+			int32(position.X),
+			int32(position.Y),
+			int32(endX),
+			int32(endY),
+			rl.Black,
+		)
 	}
 }
 
-// GetEnemies returns all enemies (for external systems that might need access)
+// wrap moves a pixel position that left the grid across a wrapping edge back
+// in from the opposite side
+func (es *EnemySystem) wrap(position rl.Vector2) rl.Vector2 {
+	wrapX, wrapY := es.navigator.Wrapping()
+	if wrapX {
+		position.X = wrapCoordinate(position.X, float32(es.config.MarginX), float32(es.config.Width*es.config.CellSize))
+	}
+	if wrapY {
+		position.Y = wrapCoordinate(position.Y, float32(es.config.MarginY), float32(es.config.Height*es.config.CellSize))
+	}
+	return position
+}
+
+// offset returns the vector from one pixel position to another, going the
+// short way across wrapping edges
+func (es *EnemySystem) offset(from, to rl.Vector2) rl.Vector2 {
+	offset := rl.Vector2{X: to.X - from.X, Y: to.Y - from.Y}
+	wrapX, wrapY := es.navigator.Wrapping()
+	if wrapX {
+		offset.X = shortestOffset(offset.X, float32(es.config.Width*es.config.CellSize))
+	}
+	if wrapY {
+		offset.Y = shortestOffset(offset.Y, float32(es.config.Height*es.config.CellSize))
+	}
+	return offset
+}
+
+// wrapCoordinate wraps a coordinate into [origin, origin+span)
+func wrapCoordinate(value, origin, span float32) float32 {
+	wrapped := float32(math.Mod(float64(value-origin), float64(span)))
+	if wrapped < 0 {
+		wrapped += span
+	}
+	return origin + wrapped
+}
+
+// shortestOffset picks the shorter of the direct and wrapped-around offsets
+func shortestOffset(offset, span float32) float32 {
+	switch {
+	case offset > span/2:
+		return offset - span
+	case offset < -span/2:
+		return offset + span
+	default:
+		return offset
+	}
+}
+
+// removeDead despawns enemies whose health has run out
+func (es *EnemySystem) removeDead() {
+	var killed []EnemyKilled
+	ecs.Each(es.world, func(entity ecs.Entity, enemy *Enemy) {
+		if enemy.IsDead() {
+			killed = append(killed, EnemyKilled{Entity: entity, Enemy: *enemy})
+			es.world.Despawn(entity)
+		}
+	})
+
+	for _, event := range killed {
+		events.Publish(es.bus, event)
+	}
+}
+
+// GetEnemies returns all enemies (for external systems that might need access).
+// The pointers are only valid until enemies are next spawned or despawned.
 func (es *EnemySystem) GetEnemies() []*Enemy {
-	return es.enemies
+	enemies := make([]*Enemy, 0, ecs.Count[Enemy](es.world))
+	ecs.Each(es.world, func(_ ecs.Entity, enemy *Enemy) {
+		enemies = append(enemies, enemy)
+	})
+	return enemies
 }
 
 // calculateSeparation keeps enemies from overlapping
-func (es *EnemySystem) calculateSeparation(enemy *Enemy) rl.Vector2 {
+func (es *EnemySystem) calculateSeparation(enemy *Enemy, neighbours []Enemy) rl.Vector2 {
 	steer := rl.Vector2{X: 0, Y: 0}
 	count := 0
 
 	// Only check nearby enemies for performance
-	for _, other := range es.enemies {
-		if other == enemy {
+	for i := range neighbours {
+		other := &neighbours[i]
+		if other == enemy || other.Level != enemy.Level {
 			continue
 		}
 
 		// Quick distance check to avoid expensive calculations
-		dx := enemy.Position.X - other.Position.X
-		dy := enemy.Position.Y - other.Position.Y
+		away := es.offset(other.Position, enemy.Position)
+		dx, dy := away.X, away.Y
 		if abs(dx) > es.config.SeparationRadius || abs(dy) > es.config.SeparationRadius {
 			continue
 		}
@@ -255,16 +586,17 @@ func (es *EnemySystem) calculateSeparation(enemy *Enemy) rl.Vector2 {
 }
 
 // calculateAlignment aligns enemy velocity with nearby enemies
-func (es *EnemySystem) calculateAlignment(enemy *Enemy) rl.Vector2 {
+func (es *EnemySystem) calculateAlignment(enemy *Enemy, neighbours []Enemy) rl.Vector2 {
 	steer := rl.Vector2{X: 0, Y: 0}
 	count := 0
 
-	for _, other := range es.enemies {
-		if other == enemy {
+	for i := range neighbours {
+		other := &neighbours[i]
+		if other == enemy || other.Level != enemy.Level {
 			continue
 		}
 
-		dist := rl.Vector2Distance(enemy.Position, other.Position)
+		dist := rl.Vector2Length(es.offset(enemy.Position, other.Position))
 		if dist > 0 && dist < es.config.AlignmentRadius {
 			steer.X += other.Velocity.X
 			steer.Y += other.Velocity.Y
@@ -281,40 +613,39 @@ func (es *EnemySystem) calculateAlignment(enemy *Enemy) rl.Vector2 {
 }
 
 // calculateCohesion pulls enemy toward center of nearby enemies
-func (es *EnemySystem) calculateCohesion(enemy *Enemy) rl.Vector2 {
-	center := rl.Vector2{X: 0, Y: 0}
+func (es *EnemySystem) calculateCohesion(enemy *Enemy, neighbours []Enemy) rl.Vector2 {
+	// Sum offsets rather than positions so the center stays correct across wrapping edges
+	toCenter := rl.Vector2{X: 0, Y: 0}
 	count := 0
 
-	for _, other := range es.enemies {
-		if other == enemy {
+	for i := range neighbours {
+		other := &neighbours[i]
+		if other == enemy || other.Level != enemy.Level {
 			continue
 		}
 
-		dist := rl.Vector2Distance(enemy.Position, other.Position)
+		offset := es.offset(enemy.Position, other.Position)
+		dist := rl.Vector2Length(offset)
 		if dist > 0 && dist < es.config.CohesionRadius {
-			center.X += other.Position.X
-			center.Y += other.Position.Y
+			toCenter.X += offset.X
+			toCenter.Y += offset.Y
 			count++
 		}
 	}
 
 	steer := rl.Vector2{X: 0, Y: 0}
 	if count > 0 {
-		center.X /= float32(count)
-		center.Y /= float32(count)
-
 		// Steer toward center
-		steer.X = (center.X - enemy.Position.X) * es.config.CohesionForce
-		steer.Y = (center.Y - enemy.Position.Y) * es.config.CohesionForce
+		steer.X = toCenter.X / float32(count) * es.config.CohesionForce
+		steer.Y = toCenter.Y / float32(count) * es.config.CohesionForce
 	}
 
 	return steer
 }
 
 // calculateObstacleAvoidance keeps enemies away from walls
-func (es *EnemySystem) calculateObstacleAvoidance(enemy *Enemy) rl.Vector2 {
+func (es *EnemySystem) calculateObstacleAvoidance(view navigation.GridView, enemy *Enemy) rl.Vector2 {
 	steer := rl.Vector2{X: 0, Y: 0}
-	grid := es.navigator.GetGrid()
 
 	// Check cells around the enemy
 	checkRadius := float32(1.5)
@@ -330,9 +661,9 @@ func (es *EnemySystem) calculateObstacleAvoidance(enemy *Enemy) rl.Vector2 {
 			checkX := gridX + dx
 			checkY := gridY + dy
 
-			// Check if this cell is an obstacle
-			if checkX >= 0 && checkX < es.config.Width && checkY >= 0 && checkY < es.config.Height {
-				if grid.Costs[checkY][checkX] == -1 {
+			// Check if this cell is an obstacle; the view wraps cells across wrapping edges
+			if cell := (navigation.Position{X: checkX, Y: checkY, Level: enemy.Level}); view.IsValidPosition(cell) {
+				if view.CostAt(cell) == -1 {
 					// Calculate repulsion from obstacle
 					obstacleX := float32(
 						es.config.MarginX + checkX*es.config.CellSize + es.config.CellSize/2,
@@ -360,7 +691,7 @@ func (es *EnemySystem) calculateObstacleAvoidance(enemy *Enemy) rl.Vector2 {
 }
 
 // calculateFlowForce gets the flow field direction for the enemy
-func (es *EnemySystem) calculateFlowForce(enemy *Enemy) rl.Vector2 {
+func (es *EnemySystem) calculateFlowForce(view navigation.GridView, enemy *Enemy) rl.Vector2 {
 	// Get current grid position
 	gridX := int(enemy.GridPos.X)
 	gridY := int(enemy.GridPos.Y)
@@ -370,17 +701,88 @@ func (es *EnemySystem) calculateFlowForce(enemy *Enemy) rl.Vector2 {
 		return rl.Vector2{X: 0, Y: 0}
 	}
 
-	currentPos := navigation.Position{X: gridX, Y: gridY}
-	flowDir, err := es.navigator.GetFlowDirection(currentPos)
-	if err != nil {
-		return rl.Vector2{X: 0, Y: 0}
-	}
+	currentPos := navigation.Position{X: gridX, Y: gridY, Level: enemy.Level}
+	flow := flowVector(view, enemy, currentPos)
 
-	// Convert grid direction to smooth force with proper strength
+	// Convert the flow vector to a force with proper strength
 	return rl.Vector2{
-		X: float32(flowDir.X) * 0.8,
-		Y: float32(flowDir.Y) * 0.8,
+		X: float32(flow.X) * 0.8,
+		Y: float32(flow.Y) * 0.8,
+	}
+}
+
+// flowVector reads the smooth flow vector of the field matching the enemy's
+// movement layer and danger weight from a snapshot
+func flowVector(view navigation.GridView, enemy *Enemy, pos navigation.Position) navigation.Vector {
+	if profile := fieldProfile(enemy.Type); profile != "" {
+		return view.ProfileFlowVectorAt(profile, pos)
+	}
+	return view.FlowVectorAt(pos)
+}
+
+// flowDirection reads the discrete flow direction of the field matching the
+// enemy's movement layer and danger weight from a snapshot
+func flowDirection(view navigation.GridView, enemy *Enemy, pos navigation.Position) navigation.Direction {
+	if profile := fieldProfile(enemy.Type); profile != "" {
+		return view.ProfileFlowAt(profile, pos)
+	}
+	return view.FlowAt(pos)
+}
+
+// RemainingCost returns the path cost from an enemy's cell to the goal along
+// the field it follows, for targeting and leak prediction
+func (es *EnemySystem) RemainingCost(enemy *Enemy) (float64, error) {
+	if profile := fieldProfile(enemy.Type); profile != "" {
+		return es.navigator.ProfileDistanceToGoal(profile, enemy.Cell())
 	}
+	return es.navigator.DistanceToGoal(enemy.Cell())
+}
+
+// Progress returns how far an enemy is along the field it follows, from 0 at
+// the start of the longest path to 1 at the goal
+func (es *EnemySystem) Progress(enemy *Enemy) (float64, error) {
+	if profile := fieldProfile(enemy.Type); profile != "" {
+		return es.navigator.ProfileNormalizedProgress(profile, enemy.Cell())
+	}
+	return es.navigator.NormalizedProgress(enemy.Cell())
+}
+
+// fieldProfile returns the profile whose field an enemy type follows, or "" for
+// the built-in ground field
+func fieldProfile(enemyType *EnemyType) string {
+	if profile := dangerProfile(enemyType); profile != "" {
+		return profile
+	}
+	if enemyType.Movement == navigation.Air {
+		return navigation.AirProfile
+	}
+	return ""
+}
+
+// dangerProfile returns the name of the danger-weighted profile an enemy type
+// follows, or "" if it ignores danger. Types sharing a movement layer and
+// weight share one profile field.
+func dangerProfile(enemyType *EnemyType) string {
+	if enemyType.DangerWeight <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s-danger-%g", enemyType.Movement, enemyType.DangerWeight)
+}
+
+// registerProfile adds the danger profile an enemy type follows if it isn't
+// registered yet. Profiles are registered at spawn so steering, which runs in
+// parallel, only ever reads fields.
+func (es *EnemySystem) registerProfile(enemyType *EnemyType) {
+	profile := dangerProfile(enemyType)
+	if profile == "" || es.navigator.HasProfile(profile) {
+		return
+	}
+
+	// AddProfile only fails if the profile already exists
+	_ = es.navigator.AddProfile(profile, navigation.FieldProfile{
+		LayerWeights: map[string]float64{navigation.DangerLayer(enemyType.Movement): float64(enemyType.DangerWeight)},
+		Movement:     enemyType.Movement,
+	})
 }
 
 // abs returns absolute value of float32
@@ -390,4 +792,3 @@ func abs(x float32) float32 {
 	}
 	return x
 }
-
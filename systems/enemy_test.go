@@ -0,0 +1,64 @@
+package systems
+
+import (
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"flow/ecs"
+	"flow/events"
+	"flow/navigation"
+)
+
+func TestEnemyStaysOnStairsThatCantReachGoal(t *testing.T) {
+	config := DefaultConfig()
+	navConfig := navigation.EightWayConfig(config.Width, config.Height)
+	navConfig.Levels = 2
+	navigator, err := navigation.NewFlowFieldNavigator(navConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer navigator.Close()
+
+	// Wall off the left side of level 0 and link it to level 1 by stairs, so
+	// neither end of the stairs can reach the goal
+	for y := range config.Height {
+		if err := navigator.SetCellType(navigation.Position{X: 3, Y: y}, navigation.Obstacle); err != nil {
+			t.Fatal(err)
+		}
+	}
+	stairs := navigation.Position{X: 0, Y: 0}
+	if err := navigator.AddStairs(stairs, navigation.Position{X: 0, Y: 0, Level: 1}, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := navigator.SetGoal(navigation.Position{X: config.Width - 1, Y: config.Height - 1}); err != nil {
+		t.Fatal(err)
+	}
+	navigator.Wait()
+
+	world := ecs.NewWorld()
+	bus := events.NewBus()
+	enemies := NewEnemySystem(world, navigator, bus, config)
+
+	teleports := 0
+	events.Subscribe(bus, func(EnemyTeleported) { teleports++ })
+
+	enemyType := DefaultEnemyType(config)
+	center := float32(config.CellSize) / 2
+	entity := world.Spawn()
+	ecs.Add(world, entity, Enemy{
+		Position: rl.Vector2{X: float32(config.MarginX) + center, Y: float32(config.MarginY) + center},
+		Radius:   enemyType.Radius,
+		Health:   enemyType.Health,
+		Type:     &enemyType,
+	})
+
+	for range 10 {
+		enemies.Update()
+	}
+
+	enemy, _ := ecs.Get[Enemy](world, entity)
+	if enemy.Level != 0 || teleports != 0 {
+		t.Fatalf("enemy took unusable stairs: level %d after %d teleports", enemy.Level, teleports)
+	}
+}
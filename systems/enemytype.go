@@ -0,0 +1,143 @@
+package systems
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"flow/navigation"
+)
+
+// minDamageFraction is the share of incoming damage that always ignores armor
+const minDamageFraction = 0.1
+
+// SteeringWeights scales each steering force when they are combined
+type SteeringWeights struct {
+	Flow       float32 `json:"flow"`
+	Separation float32 `json:"separation"`
+	Alignment  float32 `json:"alignment"`
+	Cohesion   float32 `json:"cohesion"`
+	Avoidance  float32 `json:"avoidance"`
+}
+
+// DefaultSteering returns the weights where the flow field dominates pathfinding
+func DefaultSteering() SteeringWeights {
+	return SteeringWeights{
+		Flow:       5.0,
+		Separation: 0.5,
+		Alignment:  0.2,
+		Cohesion:   0.1,
+		Avoidance:  10.0,
+	}
+}
+
+// EnemyType describes the stats and behaviour shared by all enemies of one kind
+type EnemyType struct {
+	Name         string          `json:"name"`
+	Speed        float32         `json:"speed"`        // Maximum speed in pixels per update
+	Health       float32         `json:"health"`       // Starting hit points
+	Radius       float32         `json:"radius"`       // Collision and draw radius in pixels
+	Armor        float32         `json:"armor"`        // Flat reduction applied to each hit
+	Bounty       int             `json:"bounty"`       // Reward for killing the enemy
+	Color        rl.Color        `json:"color"`        // Fill colour when drawn
+	Steering     SteeringWeights `json:"steering"`     // Steering force weights, defaults if omitted
+	DangerWeight float32         `json:"dangerWeight"` // How strongly turret coverage is avoided (0 ignores it)
+
+	Movement navigation.MovementLayer `json:"movement"` // Ground units path around obstacles, air units fly over them
+}
+
+// DefaultEnemyType returns the basic enemy built from the system configuration
+func DefaultEnemyType(cfg Config) EnemyType {
+	return EnemyType{
+		Name:     "basic",
+		Speed:    cfg.UnitSpeed,
+		Health:   10,
+		Radius:   4.0,
+		Bounty:   1,
+		Color:    rl.Red,
+		Steering: DefaultSteering(),
+	}
+}
+
+// validate checks that the enemy type has a name and usable stats
+func (t EnemyType) validate() error {
+	if t.Name == "" || t.Speed <= 0 || t.Health <= 0 || t.Radius <= 0 || t.Armor < 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidEnemyType, t.Name)
+	}
+	return nil
+}
+
+// EnemyTypeRegistry holds the enemy types available for spawning
+type EnemyTypeRegistry struct {
+	types map[string]*EnemyType
+	names []string // Registration order
+}
+
+// NewEnemyTypeRegistry creates an empty enemy type registry
+func NewEnemyTypeRegistry() *EnemyTypeRegistry {
+	return &EnemyTypeRegistry{
+		types: make(map[string]*EnemyType),
+	}
+}
+
+// Register adds an enemy type, filling in default steering weights if none are set
+func (r *EnemyTypeRegistry) Register(enemyType EnemyType) error {
+	if err := enemyType.validate(); err != nil {
+		return err
+	}
+	if _, ok := r.types[enemyType.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrEnemyTypeExists, enemyType.Name)
+	}
+
+	if enemyType.Steering == (SteeringWeights{}) {
+		enemyType.Steering = DefaultSteering()
+	}
+
+	r.types[enemyType.Name] = &enemyType
+	r.names = append(r.names, enemyType.Name)
+	return nil
+}
+
+// Get returns the enemy type registered under the given name
+func (r *EnemyTypeRegistry) Get(name string) (*EnemyType, error) {
+	enemyType, ok := r.types[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEnemyType, name)
+	}
+	return enemyType, nil
+}
+
+// Names returns the registered type names in registration order
+func (r *EnemyTypeRegistry) Names() []string {
+	return r.names
+}
+
+// Load registers every enemy type from a JSON array
+func (r *EnemyTypeRegistry) Load(reader io.Reader) error {
+	var enemyTypes []EnemyType
+	if err := json.NewDecoder(reader).Decode(&enemyTypes); err != nil {
+		return fmt.Errorf("decode enemy types: %w", err)
+	}
+
+	for _, enemyType := range enemyTypes {
+		if err := r.Register(enemyType); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LoadFile registers every enemy type from a JSON data file
+func (r *EnemyTypeRegistry) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return r.Load(file)
+}
@@ -0,0 +1,18 @@
+package systems
+
+import "errors"
+
+// Game system errors
+var (
+	ErrInvalidEnemyType = errors.New("enemy type is missing a name or has non-positive stats")
+	ErrEnemyTypeExists  = errors.New("enemy type already registered")
+	ErrUnknownEnemyType = errors.New("enemy type not registered")
+
+	ErrInvalidBuildingDef = errors.New("invalid building definition")
+	ErrBuildingDefExists  = errors.New("building definition already registered")
+	ErrUnknownBuildingDef = errors.New("building definition not registered")
+
+	ErrUnknownTurret    = errors.New("turret not found")
+	ErrMaxLevel         = errors.New("turret is already at its maximum level")
+	ErrInsufficientGold = errors.New("not enough gold")
+)
@@ -0,0 +1,44 @@
+package systems
+
+import (
+	"flow/ecs"
+	"flow/navigation"
+)
+
+// Enemy events carry a copy of the component, since the entity may be
+// despawned before subscribers run.
+
+// EnemySpawned is published when an enemy enters the field
+type EnemySpawned struct {
+	Entity ecs.Entity
+	Enemy  Enemy
+}
+
+// EnemyKilled is published when an enemy's health runs out and it is despawned
+type EnemyKilled struct {
+	Entity ecs.Entity
+	Enemy  Enemy
+}
+
+// EnemyLeaked is published when an enemy reaches the goal
+type EnemyLeaked struct {
+	Entity ecs.Entity
+	Enemy  Enemy
+}
+
+// EnemyTeleported is published when an enemy is moved through a portal
+type EnemyTeleported struct {
+	Entity ecs.Entity
+	Enemy  Enemy
+	Portal navigation.Portal
+}
+
+// BuildingPlaced is published after a building is placed and costs are updated
+type BuildingPlaced struct {
+	Building *Building
+}
+
+// BuildingRemoved is published after a building is removed and costs are updated
+type BuildingRemoved struct {
+	Building *Building
+}
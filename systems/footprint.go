@@ -0,0 +1,82 @@
+package systems
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"flow/navigation"
+)
+
+// Footprint lists the cells a building covers as offsets from its origin cell
+type Footprint []navigation.Position
+
+// Common building footprints
+var (
+	SingleCell = Footprint{{X: 0, Y: 0}}
+	Square2x2  = Footprint{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}}
+	LShape     = Footprint{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}}
+)
+
+// Rotated returns the footprint turned clockwise by the given number of quarter
+// turns, shifted so its smallest offsets are zero again
+func (f Footprint) Rotated(quarterTurns int) Footprint {
+	turns := ((quarterTurns % 4) + 4) % 4
+	if turns == 0 || len(f) == 0 {
+		return f
+	}
+
+	rotated := make(Footprint, len(f))
+	for i, cell := range f {
+		for range turns {
+			cell = navigation.Position{X: -cell.Y, Y: cell.X}
+		}
+		rotated[i] = cell
+	}
+
+	// Normalise so the footprint stays anchored at its origin cell
+	minX, minY := rotated[0].X, rotated[0].Y
+	for _, cell := range rotated {
+		minX = min(minX, cell.X)
+		minY = min(minY, cell.Y)
+	}
+	for i := range rotated {
+		rotated[i].X -= minX
+		rotated[i].Y -= minY
+	}
+
+	return rotated
+}
+
+// Cells returns the absolute grid positions covered when placed at origin
+func (f Footprint) Cells(origin navigation.Position) []navigation.Position {
+	cells := make([]navigation.Position, len(f))
+	for i, offset := range f {
+		cells[i] = navigation.Position{X: origin.X + offset.X, Y: origin.Y + offset.Y}
+	}
+	return cells
+}
+
+// UnmarshalJSON decodes a footprint drawn as rows of text where '#' marks a
+// covered cell, e.g. ["#.", "#.", "##"] for an L-shape
+func (f *Footprint) UnmarshalJSON(data []byte) error {
+	var rows []string
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return fmt.Errorf("footprint must be a list of rows: %w", err)
+	}
+
+	footprint := Footprint{}
+	for y, row := range rows {
+		for x, cell := range row {
+			switch cell {
+			case '#':
+				footprint = append(footprint, navigation.Position{X: x, Y: y})
+			case '.', ' ':
+			default:
+				return fmt.Errorf("unexpected footprint cell %q at (%d, %d)", cell, x, y)
+			}
+		}
+	}
+
+	*f = footprint
+	return nil
+}
@@ -0,0 +1,121 @@
+package systems
+
+import (
+	"fmt"
+	"testing"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"flow/ecs"
+	"flow/events"
+	"flow/navigation"
+)
+
+// newCrowd returns an enemy system on a 20x20 grid with count enemies packed
+// into its top rows, far enough from the goal that none leak and respawn at
+// random
+func newCrowd(tb testing.TB, workers, count int) (*EnemySystem, *navigation.FlowFieldNavigator) {
+	tb.Helper()
+	config := DefaultConfig()
+	config.Width, config.Height = 20, 20
+	config.Workers = workers
+
+	navigator, err := navigation.NewFlowFieldNavigator(navigation.EightWayConfig(config.Width, config.Height))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(navigator.Close)
+	for x := 4; x < 16; x++ {
+		if err := navigator.SetCellType(navigation.Position{X: x, Y: 8}, navigation.Obstacle); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	if err := navigator.SetGoal(navigation.Position{X: config.Width - 1, Y: config.Height - 1}); err != nil {
+		tb.Fatal(err)
+	}
+
+	world := ecs.NewWorld()
+	enemies := NewEnemySystem(world, navigator, events.NewBus(), config)
+
+	basic := DefaultEnemyType(config)
+	scout := DefaultEnemyType(config)
+	scout.Name = "scout"
+	scout.DangerWeight = 2
+	enemies.registerProfile(&basic)
+	enemies.registerProfile(&scout)
+	navigator.Wait()
+
+	for i := range count {
+		enemyType := &basic
+		if i%3 == 0 {
+			enemyType = &scout
+		}
+		// Crowd enemies a few pixels apart so the flocking forces all apply
+		position := rl.Vector2{
+			X: float32(config.MarginX) + float32(i%40)*float32(config.CellSize)/2 + float32(i%7),
+			Y: float32(config.MarginY) + float32(i/40)*8 + 10,
+		}
+		ecs.Add(world, world.Spawn(), Enemy{
+			Position:  position,
+			TargetPos: position,
+			GridPos:   rl.Vector2{X: (position.X - float32(config.MarginX)) / float32(config.CellSize), Y: (position.Y - float32(config.MarginY)) / float32(config.CellSize)},
+			Radius:    enemyType.Radius,
+			Health:    enemyType.Health,
+			Type:      enemyType,
+		})
+	}
+	return enemies, navigator
+}
+
+func TestEnemyUpdateIndependentOfWorkers(t *testing.T) {
+	const count, ticks = 200, 50
+
+	run := func(workers int) []rl.Vector2 {
+		enemies, _ := newCrowd(t, workers, count)
+		for range ticks {
+			enemies.Update()
+		}
+
+		var positions []rl.Vector2
+		for _, enemy := range enemies.GetEnemies() {
+			positions = append(positions, enemy.Position)
+		}
+		return positions
+	}
+
+	serial, parallel := run(1), run(8)
+	if len(serial) != count || len(parallel) != count {
+		t.Fatalf("%d and %d enemies left, want %d", len(serial), len(parallel), count)
+	}
+
+	// Guard against a crowd that never moved, which would match trivially
+	start, _ := newCrowd(t, 1, count)
+	moved := 0
+	for i, enemy := range start.GetEnemies() {
+		if enemy.Position != serial[i] {
+			moved++
+		}
+	}
+	if moved < count/2 {
+		t.Fatalf("only %d of %d enemies moved", moved, count)
+	}
+	for i := range serial {
+		if serial[i] != parallel[i] {
+			t.Errorf("enemy %d at %v with 8 workers, %v with 1", i, parallel[i], serial[i])
+		}
+	}
+}
+
+func BenchmarkEnemyUpdate(b *testing.B) {
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			enemies, _ := newCrowd(b, workers, 1000)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				enemies.Update()
+			}
+		})
+	}
+}
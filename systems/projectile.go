@@ -0,0 +1,68 @@
+package systems
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"flow/ecs"
+)
+
+// Projectile is a shot homing in on an enemy entity
+type Projectile struct {
+	Position rl.Vector2    // Current position in pixels
+	Target   ecs.Entity    // Enemy the projectile homes in on
+	Speed    float32       // Pixels travelled per update
+	Damage   float64       // Damage dealt on impact
+	Effect   *StatusEffect // Applied to the target on impact, if set
+}
+
+// ProjectileSystem moves projectiles and resolves their impacts
+type ProjectileSystem struct {
+	world  *ecs.World
+	config Config
+}
+
+func NewProjectileSystem(world *ecs.World, cfg Config) *ProjectileSystem {
+	return &ProjectileSystem{
+		world:  world,
+		config: cfg,
+	}
+}
+
+// Update moves every projectile toward its target, applying damage and effects
+// on impact. Projectiles whose target is gone are despawned.
+func (ps *ProjectileSystem) Update() {
+	now := clockOf(ps.world).Now
+
+	ecs.Each(ps.world, func(entity ecs.Entity, projectile *Projectile) {
+		target, ok := ecs.Get[Enemy](ps.world, projectile.Target)
+		if !ok || target.IsDead() {
+			ps.world.Despawn(entity)
+			return
+		}
+
+		// Impact once the projectile would reach the target's edge this update
+		dist := rl.Vector2Distance(projectile.Position, target.Position)
+		if dist <= projectile.Speed+target.Radius {
+			target.TakeDamage(float32(projectile.Damage))
+			if projectile.Effect != nil {
+				target.ApplyEffect(*projectile.Effect, now)
+			}
+			ps.world.Despawn(entity)
+			return
+		}
+
+		direction := rl.Vector2Scale(rl.Vector2Subtract(target.Position, projectile.Position), projectile.Speed/dist)
+		projectile.Position = rl.Vector2Add(projectile.Position, direction)
+	})
+}
+
+// Draw renders every projectile
+func (ps *ProjectileSystem) Draw() {
+	ecs.Each(ps.world, func(_ ecs.Entity, projectile *Projectile) {
+		color := rl.Black
+		if projectile.Effect != nil {
+			color = rl.SkyBlue
+		}
+		rl.DrawCircleV(projectile.Position, 2.5, color)
+	})
+}
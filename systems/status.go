@@ -0,0 +1,184 @@
+package systems
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// StatusKind identifies a status effect that can be applied to an enemy
+type StatusKind int
+
+const (
+	StatusSlow   StatusKind = iota // Reduces speed by Magnitude (0..1)
+	StatusStun                     // Disables steering and movement
+	StatusBurn                     // Deals Magnitude damage per second
+	StatusPoison                   // Deals Magnitude damage per second per stack
+)
+
+// String returns the lowercase name of the status kind
+func (k StatusKind) String() string {
+	switch k {
+	case StatusSlow:
+		return "slow"
+	case StatusStun:
+		return "stun"
+	case StatusBurn:
+		return "burn"
+	case StatusPoison:
+		return "poison"
+	default:
+		return fmt.Sprintf("StatusKind(%d)", int(k))
+	}
+}
+
+// MarshalText encodes the status kind by name
+func (k StatusKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes a status kind from its name
+func (k *StatusKind) UnmarshalText(text []byte) error {
+	for kind := StatusSlow; kind <= StatusPoison; kind++ {
+		if kind.String() == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown status kind %q", text)
+}
+
+// StackRule decides how a new effect combines with active effects of the same kind
+type StackRule int
+
+const (
+	// StackStrongest keeps a single instance, replaced only by an effect at least as strong
+	StackStrongest StackRule = iota
+	// StackIndependent runs each application separately, up to a maximum number of stacks
+	StackIndependent
+)
+
+// stackingRules lists how each status kind stacks
+var stackingRules = map[StatusKind]struct {
+	Rule      StackRule
+	MaxStacks int
+}{
+	StatusSlow:   {Rule: StackStrongest},
+	StatusStun:   {Rule: StackStrongest},
+	StatusBurn:   {Rule: StackStrongest},
+	StatusPoison: {Rule: StackIndependent, MaxStacks: 5},
+}
+
+// StatusEffect is a timed modifier applied to an enemy
+type StatusEffect struct {
+	Kind      StatusKind `json:"kind"`
+	Magnitude float32    `json:"magnitude"` // Slow fraction, or damage per second for burn and poison
+	Duration  float64    `json:"duration"`  // Seconds of simulation time the effect lasts
+
+	expiresAt float64 // Simulation time at which the effect ends
+}
+
+// ApplyEffect adds a status effect to the enemy following the kind's stacking rule
+func (e *Enemy) ApplyEffect(effect StatusEffect, now float64) {
+	effect.expiresAt = now + effect.Duration
+	rule := stackingRules[effect.Kind]
+
+	switch rule.Rule {
+	case StackStrongest:
+		for i := range e.Effects {
+			active := &e.Effects[i]
+			if active.Kind != effect.Kind {
+				continue
+			}
+			// A stronger effect replaces the active one outright, an equal one
+			// only extends it, and a weaker one is ignored
+			switch {
+			case effect.Magnitude > active.Magnitude:
+				*active = effect
+			case effect.Magnitude == active.Magnitude:
+				active.expiresAt = max(active.expiresAt, effect.expiresAt)
+			}
+			return
+		}
+
+	case StackIndependent:
+		stacks := 0
+		oldest := -1
+		for i, active := range e.Effects {
+			if active.Kind != effect.Kind {
+				continue
+			}
+			stacks++
+			if oldest == -1 || active.expiresAt < e.Effects[oldest].expiresAt {
+				oldest = i
+			}
+		}
+
+		// Replace the stack closest to expiring once the cap is reached
+		if rule.MaxStacks > 0 && stacks >= rule.MaxStacks {
+			e.Effects[oldest] = effect
+			return
+		}
+	}
+
+	e.Effects = append(e.Effects, effect)
+}
+
+// HasEffect reports whether the enemy has an active effect of the given kind
+func (e *Enemy) HasEffect(kind StatusKind) bool {
+	for _, effect := range e.Effects {
+		if effect.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStunned reports whether the enemy's steering is disabled
+func (e *Enemy) IsStunned() bool {
+	return e.HasEffect(StatusStun)
+}
+
+// SpeedMultiplier returns the fraction of its base speed the enemy may move at
+func (e *Enemy) SpeedMultiplier() float32 {
+	multiplier := float32(1)
+	for _, effect := range e.Effects {
+		if effect.Kind == StatusSlow {
+			multiplier = min(multiplier, 1-effect.Magnitude)
+		}
+	}
+	return max(multiplier, 0)
+}
+
+// updateEffects applies damage over time for one step and drops expired effects
+func (e *Enemy) updateEffects(now float64, dt float32) {
+	active := e.Effects[:0]
+	for _, effect := range e.Effects {
+		switch effect.Kind {
+		case StatusBurn, StatusPoison:
+			// Damage over time bypasses armor
+			e.Health -= effect.Magnitude * dt
+		}
+
+		if now < effect.expiresAt {
+			active = append(active, effect)
+		}
+	}
+	e.Effects = active
+}
+
+// statusColor returns the outline colour for the enemy's most severe effect
+func statusColor(enemy *Enemy) (rl.Color, bool) {
+	switch {
+	case enemy.HasEffect(StatusStun):
+		return rl.Yellow, true
+	case enemy.HasEffect(StatusBurn):
+		return rl.Orange, true
+	case enemy.HasEffect(StatusPoison):
+		return rl.Lime, true
+	case enemy.HasEffect(StatusSlow):
+		return rl.SkyBlue, true
+	default:
+		return rl.Color{}, false
+	}
+}
@@ -0,0 +1,100 @@
+package systems
+
+import "testing"
+
+func TestStrongestEffectStacking(t *testing.T) {
+	tests := []struct {
+		name          string
+		magnitude     float32
+		wantMagnitude float32
+		wantExpiry    float64
+	}{
+		{"stronger replaces", 0.5, 0.5, 3},
+		{"equal extends", 0.3, 0.3, 3},
+		{"weaker is ignored", 0.1, 0.3, 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var enemy Enemy
+			enemy.ApplyEffect(StatusEffect{Kind: StatusSlow, Magnitude: 0.3, Duration: 2}, 0)
+			enemy.ApplyEffect(StatusEffect{Kind: StatusSlow, Magnitude: test.magnitude, Duration: 2}, 1)
+
+			if len(enemy.Effects) != 1 {
+				t.Fatalf("%d slow effects, want 1", len(enemy.Effects))
+			}
+			effect := enemy.Effects[0]
+			if effect.Magnitude != test.wantMagnitude || effect.expiresAt != test.wantExpiry {
+				t.Errorf("slow = %v until %v, want %v until %v", effect.Magnitude, effect.expiresAt, test.wantMagnitude, test.wantExpiry)
+			}
+		})
+	}
+}
+
+func TestEqualEffectDoesNotShorten(t *testing.T) {
+	var enemy Enemy
+	enemy.ApplyEffect(StatusEffect{Kind: StatusBurn, Magnitude: 4, Duration: 5}, 0)
+	enemy.ApplyEffect(StatusEffect{Kind: StatusBurn, Magnitude: 4, Duration: 1}, 1)
+
+	if expiry := enemy.Effects[0].expiresAt; expiry != 5 {
+		t.Errorf("burn expires at %v, want 5", expiry)
+	}
+}
+
+func TestPoisonStacksAreCapped(t *testing.T) {
+	var enemy Enemy
+	for i := range 5 {
+		// The second stack is applied with the shortest duration, so it is
+		// closest to expiring even though it isn't the oldest
+		duration := 10.0
+		if i == 1 {
+			duration = 2
+		}
+		enemy.ApplyEffect(StatusEffect{Kind: StatusPoison, Magnitude: 1, Duration: duration}, float64(i))
+	}
+	if len(enemy.Effects) != 5 {
+		t.Fatalf("%d poison stacks, want 5", len(enemy.Effects))
+	}
+
+	enemy.ApplyEffect(StatusEffect{Kind: StatusPoison, Magnitude: 2, Duration: 10}, 5)
+	if len(enemy.Effects) != 5 {
+		t.Fatalf("%d poison stacks after the cap, want 5", len(enemy.Effects))
+	}
+	replaced := enemy.Effects[1]
+	if replaced.Magnitude != 2 || replaced.expiresAt != 15 {
+		t.Errorf("stack 1 = %v until %v, want the new stack", replaced.Magnitude, replaced.expiresAt)
+	}
+	for i, effect := range enemy.Effects {
+		if i != 1 && effect.Magnitude != 1 {
+			t.Errorf("stack %d was replaced", i)
+		}
+	}
+}
+
+func TestEffectsExpireOnSimulationClock(t *testing.T) {
+	clock := &Clock{Step: 0.25}
+	enemy := Enemy{Health: 10}
+	enemy.ApplyEffect(StatusEffect{Kind: StatusBurn, Magnitude: 2, Duration: 1}, clock.Now)
+
+	// The effect lasts exactly four steps of simulation time, however long
+	// each step takes in real time
+	for step := 1; step <= 4; step++ {
+		clock.Tick()
+		enemy.updateEffects(clock.Now, clock.Step)
+		if active := enemy.HasEffect(StatusBurn); active != (step < 4) {
+			t.Fatalf("after %d steps: burn active = %v", step, active)
+		}
+	}
+	if enemy.Health != 8 {
+		t.Errorf("health = %v, want 8 after one second of burning", enemy.Health)
+	}
+
+	// A paused clock keeps effects running
+	enemy.ApplyEffect(StatusEffect{Kind: StatusSlow, Magnitude: 0.5, Duration: 1}, clock.Now)
+	for range 100 {
+		enemy.updateEffects(clock.Now, 0)
+	}
+	if !enemy.HasEffect(StatusSlow) {
+		t.Error("slow expired while the clock was stopped")
+	}
+}
@@ -3,50 +3,266 @@ package systems
 import (
 	"fmt"
 	"math"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+
+	"flow/ecs"
+	"flow/navigation"
+)
+
+// TargetMask selects which movement layers a turret can hit
+type TargetMask int
+
+const (
+	TargetGround TargetMask = 1 << iota
+	TargetAir
+
+	TargetBoth = TargetGround | TargetAir
 )
 
+// UnmarshalText decodes a target mask from "ground", "air" or "both"
+func (m *TargetMask) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "ground":
+		*m = TargetGround
+	case "air":
+		*m = TargetAir
+	case "both":
+		*m = TargetBoth
+	default:
+		return fmt.Errorf("unknown turret target mask %q", text)
+	}
+	return nil
+}
+
+// TurretBuff holds the stat bonuses a turret receives from nearby support auras
+type TurretBuff struct {
+	RangeBonus            int
+	AttackSpeedMultiplier float64
+	DamageMultiplier      float64
+}
+
+// TurretLevel holds a turret's stats at one upgrade level and the cost to reach it
+type TurretLevel struct {
+	AttackRange int     `json:"attackRange"`
+	AttackSpeed float64 `json:"attackSpeed"`
+	Damage      float64 `json:"damage"`
+	Cost        int     `json:"cost"`
+}
+
 type Turret struct {
-	PositionX   int
-	PositionY   int
-	AttackRange int
-	AttackSpeed float64
+	Level       int           `json:"-"` // Current upgrade level, starting at 1
+	PositionX   int           `json:"-"`
+	PositionY   int           `json:"-"`
+	AttackRange int           `json:"attackRange"`
+	AttackSpeed float64       `json:"attackSpeed"` // Attacks per second
+	Damage      float64       `json:"damage"`
+	Targets     TargetMask    `json:"targets"`
+	Effect      *StatusEffect `json:"effect"` // Applied to each enemy hit, if set
+
+	Buff       TurretBuff `json:"-"` // Bonuses from support auras, recomputed on placement
+	nextAttack float64    // Simulation time when the turret may fire again
+}
+
+// BasicTurret returns a damage-dealing turret that hits ground and air
+func BasicTurret(gridX, gridY int) Turret {
+	return Turret{
+		PositionX:   gridX,
+		PositionY:   gridY,
+		AttackRange: 3,
+		AttackSpeed: 1.0,
+		Damage:      1.0,
+		Targets:     TargetBoth,
+	}
+}
+
+// SlowTower returns a turret that slows enemies instead of damaging them
+func SlowTower(gridX, gridY int) Turret {
+	return Turret{
+		PositionX:   gridX,
+		PositionY:   gridY,
+		AttackRange: 2,
+		AttackSpeed: 2.0,
+		Targets:     TargetBoth,
+		Effect: &StatusEffect{
+			Kind:      StatusSlow,
+			Magnitude: 0.5,
+			Duration:  1.5,
+		},
+	}
+}
+
+// CanTarget reports whether the turret can hit units on the given movement layer
+func (t Turret) CanTarget(movement navigation.MovementLayer) bool {
+	switch movement {
+	case navigation.Ground:
+		return t.Targets&TargetGround != 0
+	case navigation.Air:
+		return t.Targets&TargetAir != 0
+	default:
+		return false
+	}
+}
+
+// Range returns the attack range including aura bonuses
+func (t Turret) Range() int {
+	return t.AttackRange + t.Buff.RangeBonus
+}
+
+// Speed returns the attacks per second including aura bonuses
+func (t Turret) Speed() float64 {
+	return t.AttackSpeed * max(t.Buff.AttackSpeedMultiplier, 1)
+}
+
+// Hit returns the damage per attack including aura bonuses
+func (t Turret) Hit() float64 {
+	return t.Damage * max(t.Buff.DamageMultiplier, 1)
+}
+
+// DPS returns the damage per second the turret deals to a single target
+func (t Turret) DPS() float64 {
+	return t.Hit() * t.Speed()
 }
 
+// Covers reports whether a grid cell lies within the turret's attack range
+func (t Turret) Covers(gridX, gridY int) bool {
+	dx := float64(t.PositionX - gridX)
+	dy := float64(t.PositionY - gridY)
+	return math.Sqrt(dx*dx+dy*dy) <= float64(t.Range())
+}
+
+// applyLevel replaces the turret's base stats with those of an upgrade level
+func (t *Turret) applyLevel(level TurretLevel) {
+	t.AttackRange = level.AttackRange
+	t.AttackSpeed = level.AttackSpeed
+	t.Damage = level.Damage
+	t.Level++
+}
+
+// TurretSystem fires projectiles from every turret entity at enemies in range
 type TurretSystem struct {
-	Turrets     []Turret
-	enemySystem *EnemySystem
-	config      Config
+	world  *ecs.World
+	config Config
 }
 
-func NewTurretSystem(enemySys *EnemySystem, cfg Config) *TurretSystem {
+func NewTurretSystem(world *ecs.World, cfg Config) *TurretSystem {
 	return &TurretSystem{
-		Turrets:     make([]Turret, 0),
-		enemySystem: enemySys,
-		config:      cfg,
+		world:  world,
+		config: cfg,
 	}
 }
 
+// SpawnTurret adds a turret entity at level 1
+func SpawnTurret(world *ecs.World, turret Turret) ecs.Entity {
+	turret.Level = 1
+
+	entity := world.Spawn()
+	ecs.Add(world, entity, turret)
+	return entity
+}
+
+// Update fires a projectile from each ready turret at its closest target
 func (ts *TurretSystem) Update() {
-	for _, turret := range ts.Turrets {
-		ts.checkEnemiesInRange(turret)
+	now := clockOf(ts.world).Now
+
+	var shots []Projectile
+	ecs.Each(ts.world, func(_ ecs.Entity, turret *Turret) {
+		if now < turret.nextAttack {
+			return
+		}
+
+		target, ok := ts.findTarget(*turret)
+		if !ok {
+			return
+		}
+
+		shots = append(shots, Projectile{
+			Position: rl.Vector2{
+				X: float32(ts.config.MarginX + turret.PositionX*ts.config.CellSize + ts.config.CellSize/2),
+				Y: float32(ts.config.MarginY + turret.PositionY*ts.config.CellSize + ts.config.CellSize/2),
+			},
+			Target: target,
+			Speed:  ts.config.ProjectileSpeed,
+			Damage: turret.Hit(),
+			Effect: turret.Effect,
+		})
+		turret.nextAttack = now + 1/turret.Speed()
+	})
+
+	for _, shot := range shots {
+		entity := ts.world.Spawn()
+		ecs.Add(ts.world, entity, shot)
 	}
 }
 
-func (ts *TurretSystem) checkEnemiesInRange(turret Turret) {
-	enemies := ts.enemySystem.GetEnemies()
-	
-	for _, enemy := range enemies {
+// findTarget returns the closest living enemy the turret can hit
+func (ts *TurretSystem) findTarget(turret Turret) (ecs.Entity, bool) {
+	var target ecs.Entity
+	found := false
+	bestDist := math.MaxFloat64
+
+	ecs.Each(ts.world, func(entity ecs.Entity, enemy *Enemy) {
+		if enemy.IsDead() || !turret.CanTarget(enemy.Type.Movement) {
+			return
+		}
+
 		// Convert enemy screen position to grid position
 		enemyGridX := int((enemy.Position.X - float32(ts.config.MarginX)) / float32(ts.config.CellSize))
 		enemyGridY := int((enemy.Position.Y - float32(ts.config.MarginY)) / float32(ts.config.CellSize))
-		
-		// Calculate distance between turret and enemy
+		if !turret.Covers(enemyGridX, enemyGridY) {
+			return
+		}
+
 		dx := float64(turret.PositionX - enemyGridX)
 		dy := float64(turret.PositionY - enemyGridY)
-		distance := math.Sqrt(dx*dx + dy*dy)
-		
-		if distance <= float64(turret.AttackRange) {
-			fmt.Println("ENEMY IN RANGE")
+		if dist := dx*dx + dy*dy; dist < bestDist {
+			bestDist = dist
+			target = entity
+			found = true
 		}
+	})
+
+	return target, found
+}
+
+// DangerCosts returns per-cell extra path costs for cells covered by the world's
+// turrets that can hit units on a movement layer, weighted by each turret's DPS
+// and the configured DangerScale
+func DangerCosts(world *ecs.World, cfg Config, movement navigation.MovementLayer) [][]int {
+	danger := make([][]float64, cfg.Height)
+	for y := range cfg.Height {
+		danger[y] = make([]float64, cfg.Width)
 	}
+
+	ecs.Each(world, func(_ ecs.Entity, turret *Turret) {
+		dps := turret.DPS()
+		if dps <= 0 || !turret.CanTarget(movement) {
+			return
+		}
+
+		// Only scan the bounding box of the turret's range
+		attackRange := turret.Range()
+		minX := max(0, turret.PositionX-attackRange)
+		maxX := min(cfg.Width-1, turret.PositionX+attackRange)
+		minY := max(0, turret.PositionY-attackRange)
+		maxY := min(cfg.Height-1, turret.PositionY+attackRange)
+
+		for y := minY; y <= maxY; y++ {
+			for x := minX; x <= maxX; x++ {
+				if turret.Covers(x, y) {
+					danger[y][x] += dps
+				}
+			}
+		}
+	})
+
+	costs := make([][]int, cfg.Height)
+	for y := range cfg.Height {
+		costs[y] = make([]int, cfg.Width)
+		for x := range cfg.Width {
+			costs[y][x] = int(math.Round(danger[y][x] * float64(cfg.DangerScale)))
+		}
+	}
+
+	return costs
 }
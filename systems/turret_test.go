@@ -0,0 +1,31 @@
+package systems
+
+import (
+	"testing"
+
+	"flow/ecs"
+	"flow/navigation"
+)
+
+func TestDangerCostsFollowTargetMask(t *testing.T) {
+	config := DefaultConfig()
+	world := ecs.NewWorld()
+
+	sniper := BasicTurret(2, 2)
+	sniper.Targets = TargetGround
+	SpawnTurret(world, sniper)
+
+	flak := BasicTurret(7, 7)
+	flak.Targets = TargetAir
+	SpawnTurret(world, flak)
+
+	ground := DangerCosts(world, config, navigation.Ground)
+	air := DangerCosts(world, config, navigation.Air)
+
+	if ground[2][2] == 0 || ground[7][7] != 0 {
+		t.Errorf("ground danger = %d under the sniper and %d under the flak, want only the sniper", ground[2][2], ground[7][7])
+	}
+	if air[7][7] == 0 || air[2][2] != 0 {
+		t.Errorf("air danger = %d under the flak and %d under the sniper, want only the flak", air[7][7], air[2][2])
+	}
+}